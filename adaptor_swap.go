@@ -0,0 +1,99 @@
+package main
+
+// Two-party atomic swap with Schnorr adaptor signatures.
+//
+// Alice has coins on chain A and wants Bob's coins on chain B.  In the real
+// protocol (AtomicDEX, scriptless scripts) both coins are first locked in
+// 2-of-2 outputs and the pre-signatures below are each party's half of the
+// spend.  To keep the core idea visible, each spend here needs only the
+// sender's signature.  See:
+//     https://github.com/BlockstreamResearch/scriptless-scripts/blob/master/md/atomic-swap.md
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+
+	"btc-practice/kmdutil"
+)
+
+func randScalar() *big.Int {
+	for {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			log.Fatal(err)
+		}
+		d := new(big.Int).SetBytes(b)
+		if d.Sign() > 0 && d.Cmp(kmdutil.CurveN()) < 0 {
+			return d
+		}
+	}
+}
+
+func auxRand() []byte {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return b
+}
+
+func main() {
+	aliceKey := randScalar()
+	bobKey := randScalar()
+	alicePub, _ := kmdutil.SchnorrPubKey(aliceKey)
+	bobPub, _ := kmdutil.SchnorrPubKey(bobKey)
+	fmt.Printf("Alice pubkey: %x\n", alicePub)
+	fmt.Printf("Bob pubkey:   %x\n\n", bobPub)
+
+	txA := kmdutil.TaggedHash("swap/tx", []byte("chain A: Alice pays Bob 10 KMD"))
+	txB := kmdutil.TaggedHash("swap/tx", []byte("chain B: Bob pays Alice 1 BTC"))
+
+	// 1. Alice picks the swap secret t and tells Bob only T = t*G.
+	t := randScalar()
+	T := kmdutil.ECBaseMul(t)
+	fmt.Printf("1. Alice's adaptor point T: %x\n", T.Serialize())
+
+	// 2. Bob pre-signs his payment to Alice under T.  Alice cannot use it
+	//    yet without revealing t.
+	preB, err := kmdutil.AdaptorSign(bobKey, txB, T, auxRand())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("2. Bob's pre-signature:    %x\n", preB.Serialize())
+	fmt.Println("   Alice verifies it:", preB.Verify(bobPub, txB, T))
+
+	// 3. Alice pre-signs her payment to Bob under the same T.
+	preA, err := kmdutil.AdaptorSign(aliceKey, txA, T, auxRand())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("3. Alice's pre-signature:  %x\n", preA.Serialize())
+	fmt.Println("   Bob verifies it:", preA.Verify(alicePub, txA, T))
+	fmt.Println("   Is it a valid signature yet?", kmdutil.SchnorrVerify(alicePub, txA, preA.Serialize()[1:]))
+
+	// 4. Alice completes Bob's pre-signature with t and broadcasts tx B,
+	//    claiming Bob's coins.  The signature is now public on chain B.
+	sigB := preB.Complete(t)
+	fmt.Printf("4. Alice claims on chain B: %x\n", sigB)
+	fmt.Println("   Chain B accepts it:", kmdutil.SchnorrVerify(bobPub, txB, sigB))
+
+	// 5. Bob reads sigB from chain B and extracts t ...
+	learned, err := preB.Extract(sigB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("5. Bob extracts t:         %x\n", learned)
+	fmt.Println("   Matches Alice's secret:", learned.Cmp(t) == 0)
+
+	// 6. ... and uses it to complete Alice's pre-signature, claiming her
+	//    coins on chain A.
+	sigA := preA.Complete(learned)
+	fmt.Printf("6. Bob claims on chain A:  %x\n", sigA)
+	fmt.Println("   Chain A accepts it:", kmdutil.SchnorrVerify(alicePub, txA, sigA))
+}
@@ -0,0 +1,70 @@
+package main
+
+// Komodo smart chain (assetchain) parameters practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	f, err := os.Open("assetchains.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	nets, skipped, err := kmdutil.RegisterAssetChains(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, e := range skipped {
+		fmt.Println("skipped:", e)
+	}
+
+	/*
+	 * Every smart chain uses the KMD address prefixes, so one key has the
+	 * same R-address on all of them.  Only the magic and ports tell the
+	 * chains apart.
+	 */
+	passHash := sha256.Sum256([]byte("myverysecretandstrongpassphrase_nonecanbreak"))
+	privateKey := new(big.Int).SetBytes(passHash[:])
+
+	// A WIF given on the command line replaces the passphrase key, e.g.
+	//     go run assetchains.go UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh
+	// An uncompressed WIF (7...) gives the addresses of the uncompressed
+	// public key.
+	compressed := true
+	if len(os.Args) > 1 {
+		privateKey, _, compressed, err = kmdutil.DecodeWIF(os.Args[1])
+		if err != nil {
+			log.Fatalf("WIF decode error: %v", err)
+		}
+	}
+	publicKey := kmdutil.ECBaseMul(privateKey)
+	serializedPublicKey := publicKey.Serialize()
+	if !compressed {
+		serializedPublicKey = publicKey.SerializeUncompressed()
+	}
+
+	fmt.Printf("%-10s %-10s %6s %6s  %s\n", "chain", "magic", "p2p", "rpc", "address")
+	for _, net := range append([]*kmdutil.Network{kmdutil.KMD}, nets...) {
+		fmt.Printf("%-10s 0x%08x %6d %6d  %s\n", net.Name, net.NetMagic, net.P2PPort, net.RPCPort,
+			kmdutil.P2PKHAddress(serializedPublicKey, net))
+	}
+
+	net, err := kmdutil.NetworkByName("rick")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\nrick rpc port: %d\n", net.RPCPort)
+}
@@ -2,51 +2,37 @@ package main
 
 import (
 	"fmt"
-	"math/big"
-)
-
-func b58(data []byte) string {
-	base := big.NewInt(58)                                                        // set new big int for dividing data bytes with 58
-	remainder := new(big.Int)                                                     // to store remainder of the data bytes
-	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz" // base 58 character set
 
-	//fmt.Println("Length of base58 characters: ", len(alphabet))
-	fmt.Println("Recieved data bytes: ", data)
+	"btc-practice/kmdutil"
+)
 
-	x := new(big.Int).SetBytes(data) // convert bytes to big integer
-	//fmt.Println("Bytes to big integer: ", x)
-	var output_string []byte // To store base58 coverted value to a temp variable
+func main() {
+	dog := kmdutil.Base58Encode([]byte("cat"))
+	fmt.Println("base58 encode:", dog)
 
-	for x.Cmp(big.NewInt(0)) != 0 {
-		x.DivMod(x, base, remainder)
-		//fmt.Println("X", x, "\nRemainder", remainder)
-		fmt.Printf("alphabet[%d]: %s\n", remainder.Int64(), string(alphabet[remainder.Int64()]))
-		output_string = append(output_string, alphabet[remainder.Int64()]) // Appending the value stored at position alphabet[remainder] to output_string
+	decoded, err := kmdutil.Base58Decode(dog)
+	if err != nil {
+		fmt.Println("base58 decode error:", err)
 	}
-	//fmt.Println("output_string value after first loop: ", string(output_string))
+	fmt.Println("base58 decode:", string(decoded))
 
-	for i := 0; data[i] == 0; i++ {
-		output_string = append(output_string, alphabet[0])
-	}
-	fmt.Println("output_string value after second loop: ", string(output_string))
+	// Empty and all-zero input used to panic the practice encoders.
+	fmt.Printf("base58 encode of []byte{}: %q\n", kmdutil.Base58Encode([]byte{}))
+	fmt.Printf("base58 encode of []byte{0, 0, 0}: %q\n\n", kmdutil.Base58Encode([]byte{0, 0, 0}))
 
-	// Reverse the array.
-	for i, j := 0, len(output_string)-1; i < j; i, j = i+1, j-1 {
-		output_string[i], output_string[j] = output_string[j], output_string[i]
+	// Mastering Bitcoin example address, page 66.
+	version, payload, err := kmdutil.Base58CheckDecode("1J7mdg5rbQyUHENYdx39WVWK7fsLpEoXZy")
+	if err != nil {
+		fmt.Println("base58check decode error:", err)
 	}
-	return string(output_string)
-}
-
-func b58decode(data string) []byte {
-	fmt.Println(data)
-	dataByte := []byte(data)
-	return dataByte
-}
+	fmt.Printf("base58check version: %d payload: %x\n", version, payload)
+	fmt.Println("base58check re-encode:", kmdutil.Base58CheckEncode(payload, version))
 
-func main() {
-	dog := b58([]byte("cat"))
-	fmt.Println("base58 encode:", dog, "\n")
+	// '0' is not part of the Base58 alphabet.
+	_, _, err = kmdutil.Base58CheckDecode("1J7mdg5rbQyUHENYdx39WVWK7fsLp0oXZy")
+	fmt.Println("base58check decode error:", err)
 
-	decoded := b58decode(dog)
-	fmt.Println("base58 decode:", decoded)
+	// Last character changed, so the checksum no longer matches.
+	_, _, err = kmdutil.Base58CheckDecode("1J7mdg5rbQyUHENYdx39WVWK7fsLpEoXZz")
+	fmt.Println("base58check decode error:", err)
 }
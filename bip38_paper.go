@@ -0,0 +1,94 @@
+package main
+
+// BIP38 passphrase-protected paper key practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	net := kmdutil.KMD
+	passphrase := "TestingOneTwoThree"
+
+	passHash := sha256.Sum256([]byte("myverysecretandstrongpassphrase_noneabletobrute"))
+	privKey, err := kmdutil.PrivateKeyFromBytes(passHash[:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// A WIF given on the command line replaces the passphrase key, e.g.
+	//     go run bip38_paper.go UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh
+	// An uncompressed WIF (7...) is encrypted with the address of the
+	// uncompressed public key.
+	compressed := true
+	if len(os.Args) > 1 {
+		privKey, _, compressed, err = kmdutil.PrivateKeyFromWIF(os.Args[1])
+		if err != nil {
+			log.Fatalf("WIF decode error: %v", err)
+		}
+	}
+	publicKey := privKey.PublicKey().Serialize()
+	if !compressed {
+		publicKey = privKey.PublicKey().SerializeUncompressed()
+	}
+	address := kmdutil.P2PKHAddress(publicKey, net)
+	fmt.Println("address:", address)
+	fmt.Println("WIF:", privKey.WIF(net, compressed))
+
+	/*
+	 * Non-EC-multiply mode: the key itself is encrypted with AES under a
+	 * scrypt key derived from the passphrase and the address hash.
+	 */
+	encrypted, err := kmdutil.BIP38Encrypt(privKey, passphrase, net, compressed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("BIP38 encrypted:", encrypted)
+	decrypted, compressed, err := kmdutil.BIP38Decrypt(encrypted, passphrase, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("BIP38 decrypted:", decrypted.WIF(net, compressed))
+	if _, _, err := kmdutil.BIP38Decrypt(encrypted, "wrong passphrase", net); err != nil {
+		fmt.Println("wrong passphrase:", err)
+	}
+	fmt.Println()
+
+	/*
+	 * EC-multiply mode: the owner hands out an intermediate code, and the
+	 * printer makes new keys from it without learning the passphrase.
+	 * The confirmation code lets the owner check the address.
+	 */
+	intermediate, err := kmdutil.BIP38Intermediate(passphrase, &kmdutil.BIP38LotSequence{Lot: 1, Sequence: 1})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("intermediate code:", intermediate)
+	encrypted, confirmation, address, err := kmdutil.BIP38EncryptFromIntermediate(intermediate, true, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("printed address:", address)
+	fmt.Println("printed key:", encrypted)
+	fmt.Println("confirmation code:", confirmation)
+
+	confirmed, err := kmdutil.BIP38VerifyConfirmation(confirmation, passphrase, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("confirmed address:", confirmed)
+	decrypted, compressed, err = kmdutil.BIP38Decrypt(encrypted, passphrase, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("owner's WIF:", decrypted.WIF(net, compressed))
+}
@@ -11,6 +11,9 @@ import (
 	//"math"
 	"errors"
 	"log"
+	"os"
+
+	"btc-practice/kmdutil"
 )
 
 type Point struct {
@@ -115,6 +118,25 @@ func main() {
 	P.y = *y
 	//fmt.Printf("%t\n", P)
 
+	// A WIF given on the command line replaces G with the public key of
+	// its private key, e.g.
+	//     go run btcbook_addr_01.go UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh
+	if len(os.Args) > 1 {
+		privateKey, net, compressed, err := kmdutil.DecodeWIF(os.Args[1])
+		if err != nil {
+			log.Fatalf("WIF decode error: %v", err)
+		}
+		publicKey := kmdutil.ECBaseMul(privateKey)
+		P.x = *publicKey.X
+		P.y = *publicKey.Y
+		serializedPublicKey := publicKey.Serialize()
+		if !compressed {
+			serializedPublicKey = publicKey.SerializeUncompressed()
+		}
+		fmt.Println("WIF:", os.Args[1], "network:", net, "compressed:", compressed)
+		fmt.Println("address:", kmdutil.P2PKHAddress(serializedPublicKey, net))
+	}
+
 	err := ec_valid(&P)
 	if err != nil {
 		fmt.Println(err)
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	// "log"
 	"math/big"
+	"os"
 
+	"btc-practice/kmdutil"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -140,7 +142,7 @@ func (Q *Point) ec_point_multiply(d *big.Int, P *Point) Point {
 func (R Point) Serialize() []byte {
 	b := R.x.Bytes()
 	fmt.Println("Check if Y is Even or Odd: ")
-	fmt.Println("R.y.Mod(big.NewInt(2))", R.y.Mod(R.y, big.NewInt(2)))
+	fmt.Println("R.y.Mod(big.NewInt(2))", new(big.Int).Mod(R.y, big.NewInt(2)))
 
 	// If the length of Public Key x bytes is lesser than 32 bytes, we need to add
 	// the required remaining bytes to the Public Key x.
@@ -163,7 +165,7 @@ func (R Point) Serialize() []byte {
 		        fmt.Println(n,"is Odd number")
 		    }
 	*/
-	if R.y.Mod(R.y, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
+	if R.y.Bit(0) == 0 {
 		fmt.Println(R.y, "is Even number")
 		fmt.Println("R.x", R.x)
 		fmt.Println("R.x Bytes", b)
@@ -198,47 +200,13 @@ func s256(data []byte) []byte {
 	return h.Sum(nil)
 }
 
-/*
- * Encode the data in Bitcoin's Base58 format.  See:
- *     https://en.bitcoin.it/wiki/Base58Check_encoding#Base58_symbol_chart
- */
-func base58(data []byte) string {
-	base := big.NewInt(58)                                                        // set new big int for dividing data bytes with 58
-	remainder := new(big.Int)                                                     // to store remainder of the data bytes
-	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz" // base 58 character set
-
-	//fmt.Println("Length of base58 characters: ", len(alphabet))
-	//fmt.Printf("Recieved data bytes: %x\n", data)
-
-	x := new(big.Int).SetBytes(data) // convert bytes to big integer
-	//fmt.Println("Bytes to big integer: ", x)
-	var output_string []byte // To store base58 coverted value to a temp variable
-
-	for x.Cmp(big.NewInt(0)) != 0 {
-		x.DivMod(x, base, remainder)
-		//fmt.Println("X", x, "\nRemainder", remainder)
-		//fmt.Printf("alphabet[%d]: %s\n", remainder.Int64(), string(alphabet[remainder.Int64()]))
-		output_string = append(output_string, alphabet[remainder.Int64()]) // Appending the value stored at position alphabet[remainder] to output_string
-	}
-	//fmt.Println("output_string value after first loop: ", string(output_string))
-
-	for i := 0; data[i] == 0; i++ {
-		output_string = append(output_string, alphabet[0])
-	}
-	//fmt.Println("output_string value after second loop: ", string(output_string))
-
-	// Reverse the array.
-	for i, j := 0, len(output_string)-1; i < j; i, j = i+1, j-1 {
-		output_string[i], output_string[j] = output_string[j], output_string[i]
-	}
-	return string(output_string)
-}
-
 func main() {
-	// btcVersionByte := []byte{0x0}
-	// btcPrivKeyVersionByte := []byte{0x80}
-	kmdVersionByte := []byte{0x3C}
-	kmdPrivKeyVersionByte := []byte{0xBC}
+	// The chain to generate the address and WIF for.  Any network from
+	// the registry works, e.g. kmdutil.BTCMainnet or kmdutil.LTC.
+	net := kmdutil.KMD
+	// SegWit addresses are shown for the Bitcoin networks, since Komodo
+	// has no SegWit.
+	segwitNets := []*kmdutil.Network{kmdutil.BTCMainnet, kmdutil.BTCTestnet, kmdutil.BTCRegtest}
 	// Please note that the following code is a demo.  Edge cases and error
 	// checking are intentionally omitted where they might otherwise distract
 	// us from the core ideas.
@@ -282,13 +250,38 @@ func main() {
 	fmt.Println("Password/Passphrase: ", passStr)
 	fmt.Printf("password hash: %x\n", passHash)
 
+	// A WIF given on the command line replaces the passphrase key, and its
+	// version byte selects the network, e.g.
+	//     go run btcbook_addr_02.go UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh
+	// An uncompressed WIF (5... or 7...) stands for the uncompressed public
+	// key, so its addresses hash that instead.
+	compressed := true
+	if len(os.Args) > 1 {
+		wifKey, wifNet, wifCompressed, err := kmdutil.DecodeWIF(os.Args[1])
+		if err != nil {
+			fmt.Println("WIF decode error:", err)
+			return
+		}
+		private_key, net, compressed = wifKey, wifNet, wifCompressed
+		fmt.Println("WIF:", os.Args[1], "network:", net, "compressed:", compressed)
+	}
+
 	// Mastering Bitcoin example privkey, which has even public key x value
 	// private_key, ok := private_key.SetString("038109007313a5807b2eccc082c8c3fbb988a973cacf1a7df9ce725c31b14776", 16)
 	// if !ok {
 	// 	log.Fatalf("big Int value did not set")
 	// 	//return errors.New("big Int value did not set")
 	// }
-	fmt.Printf("private_key: %d\n", private_key)
+	// PrivateKey keeps the canonical 32-byte form, where
+	// private_key.Bytes() would drop leading zero bytes and give a short,
+	// invalid WIF.
+	privKey, err := kmdutil.NewPrivateKey(private_key)
+	if err != nil {
+		fmt.Println("private key error:", err)
+		return
+	}
+	fmt.Println("private_key:", privKey.Decimal())
+	fmt.Println("private_key hex:", privKey.Hex())
 
 	var G Point
 	G = ec_G()
@@ -296,11 +289,14 @@ func main() {
 	fmt.Printf("Gy: %d\n", G.y)
 
 	var publicKey Point
-	publicKey.ec_point_multiply(private_key, &G)
+	publicKey.ec_point_multiply(privKey.Int(), &G)
 	fmt.Printf("\npublicKey.x %d\n", publicKey.x)
 	fmt.Printf("publicKey.y %d\n", publicKey.y)
 
 	serializedPublicKey := publicKey.Serialize()
+	if !compressed {
+		serializedPublicKey = kmdutil.Point{X: publicKey.x, Y: publicKey.y}.SerializeUncompressed()
+	}
 	fmt.Printf("serializedPublicKey: %x\n", serializedPublicKey)
 
 	publicKeyHash := r160(s256(serializedPublicKey))
@@ -312,7 +308,7 @@ func main() {
 	 *     Mastering Bitcoin, page 58
 	 *     https://en.bitcoin.it/wiki/Technical_background_of_version_1_Bitcoin_addresses#How_to_create_Bitcoin_Address - Steps 5-7.
 	 */
-	version := kmdVersionByte
+	version := append([]byte{}, net.PubKeyHashAddrID...)
 	fmt.Printf("Bitcoin version byte: %d\n", version)
 	versionPlusHash := append(version, publicKeyHash...)
 	fmt.Printf("bitcoin version + pubkey hash: %d\n", versionPlusHash)
@@ -324,39 +320,150 @@ func main() {
 	 * Bitcoin's Base58Check format.  See:
 	 *     Mastering Bitcoin, page 66.
 	 */
-	address := base58(append(versionPlusHash, checksum...))
+	address := kmdutil.Base58Encode(append(versionPlusHash, checksum...))
 	fmt.Println("address:", address, "\n")
 
+	fmt.Println("btc address:", kmdutil.P2PKHAddress(serializedPublicKey, kmdutil.BTCMainnet))
+
+	// Bitcoin Cash shares Bitcoin's version bytes, and writes the same
+	// r160(s256(pubkey)) hash as a CashAddr.  See:
+	//     https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+	cashAddress, err := kmdutil.LegacyToCashAddr(kmdutil.P2PKHAddress(serializedPublicKey, kmdutil.BCH), kmdutil.BCH)
+	if err != nil {
+		fmt.Println("cashaddr error:", err)
+	}
+	legacyAddress, _ := kmdutil.CashAddrToLegacy(cashAddress, kmdutil.BCH)
+	fmt.Println("bch address:", cashAddress, "legacy:", legacyAddress)
+
+	// Cosmos-SDK chains use the same Hash160 in Bech32 under the chain's
+	// HRP, e.g. "cosmos" or "osmo".  They, and the XRP Ledger below, only
+	// know compressed keys, whatever the WIF says.
+	compressedPublicKey := publicKey.Serialize()
+	cosmosAddress, _ := kmdutil.CosmosAccAddress(compressedPublicKey, "cosmos")
+	cosmosValoper, _ := kmdutil.CosmosValOperAddress(compressedPublicKey, "cosmos")
+	cosmosPubKey, _ := kmdutil.CosmosPubKey(compressedPublicKey, "cosmos")
+	fmt.Println("cosmos address:", cosmosAddress)
+	fmt.Println("cosmos valoper:", cosmosValoper)
+	fmt.Println("cosmos pubkey:", cosmosPubKey)
+
+	// The XRP Ledger writes the Hash160 account ID in Base58Check too,
+	// but with the Ripple alphabet, so addresses start with 'r'.  Its
+	// family seed (s...) is 16 bytes of entropy in the same alphabet.
+	// rippled derives the seed from the passphrase with SHA-512 rather
+	// than the hash above, and the account key from the seed, so the
+	// seed's account differs from the address of this key.
+	xrpAddress, _ := kmdutil.XRPAddress(compressedPublicKey)
+	fmt.Println("xrp address:", xrpAddress)
+	xrpEntropy := kmdutil.XRPSeedFromPassphrase(passStr)
+	xrpSeed, _ := kmdutil.EncodeXRPSeed(xrpEntropy)
+	xrpSeedKey, _ := kmdutil.XRPSeedKey(xrpEntropy)
+	xrpSeedAddress, _ := kmdutil.XRPAddress(xrpSeedKey.PublicKey().Serialize())
+	fmt.Println("xrp seed for the passphrase:", xrpSeed, "address:", xrpSeedAddress)
+
 	/*
-	 *	https://www.mobilefish.com/services/cryptocurrency/cryptocurrency.html#refPrivateKeyHex
+	 * The same key also controls an EVM account (Komodo bridges,
+	 * AtomicDEX ERC20 coins): the last 20 bytes of the Keccak-256 of the
+	 * uncompressed public key x || y, with EIP-55 mixed-case checksum.  See:
+	 *     https://github.com/ethereum/EIPs/blob/master/EIPS/eip-55.md
+	 */
+	evmAddress := kmdutil.EVMAddress(kmdutil.Point{X: publicKey.x, Y: publicKey.y})
+	fmt.Println("evm address:", evmAddress)
+
+	// Tron takes the same 20 bytes, with a 0x41 prefix in Base58Check.
+	tronAddress := kmdutil.TronAddress(kmdutil.Point{X: publicKey.x, Y: publicKey.y})
+	tronHex, _ := kmdutil.TronBase58ToHex(tronAddress)
+	fmt.Println("tron address:", tronAddress, "hex:", tronHex)
+	fmt.Println()
+
+	/*
+	 * Zcash-family chains use two-byte version prefixes (t1..., zn...), so
+	 * the prefix is a byte slice rather than a single version byte.
+	 * Decoding picks the network by the longest matching prefix.
 	 */
-	privKeyVersion := kmdPrivKeyVersionByte                                       // version byte to add as prefix for private key
-	privKeyPlusVersion := append(privKeyVersion, private_key.Bytes()...) // privkey version + privkey hash
-	privKeyChecksum := s256(s256(privKeyPlusVersion))[:4]                // first 4 bytes of double hashed (privkey version + privkey hash)
-	fmt.Printf("Bitcoin Private Key version byte: %d\n", privKeyVersion)
-	fmt.Printf("bitcoin version + private key hash: %x   byte length: %d\n", privKeyPlusVersion, len(privKeyPlusVersion))
-	fmt.Printf("privKeyChecksum: %x\n", privKeyChecksum)
+	for _, zNet := range []*kmdutil.Network{kmdutil.ZEC, kmdutil.ZEN} {
+		zAddr := kmdutil.P2PKHAddress(serializedPublicKey, zNet)
+		decNet, _, decHash, err := kmdutil.DecodeBase58Address(zAddr)
+		if err != nil {
+			fmt.Println("address decode error:", err)
+			continue
+		}
+		fmt.Printf("%s address: %s  version: %x  decoded as: %s %x\n",
+			zNet, zAddr, zNet.PubKeyHashAddrID, decNet, decHash)
+	}
+	fmt.Println()
 
-	privKeyAddChecksumUncomp := append(privKeyPlusVersion, privKeyChecksum...) // privkey version + privkey hash + extra byte + last
-	fmt.Printf("Privkey version + privkey hash + Uncompressed Checksum: %x   byte length: %d\n", privKeyAddChecksumUncomp, len(privKeyAddChecksumUncomp))
+	/*
+	 * Native SegWit (P2WPKH) address: witness version 0 with the same
+	 * 20-byte public key hash as the program, encoded in Bech32.  See:
+	 *     https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#segwit-address-format
+	 */
+	for _, segwitNet := range segwitNets {
+		segwitAddress, err := kmdutil.P2WPKHAddress(serializedPublicKey, segwitNet)
+		if err != nil {
+			fmt.Println("P2WPKH address error:", err)
+			continue
+		}
+		fmt.Printf("P2WPKH address (%s): %s\n", segwitNet, segwitAddress)
+	}
+	fmt.Println()
 
-	wifUncompressed := base58(privKeyAddChecksumUncomp)
-	fmt.Println("WIF (Uncompressed):", wifUncompressed, "\n")
+	/*
+	 * Nested SegWit (P2SH-P2WPKH) address: the P2WPKH program
+	 * OP_0 <publicKeyHash> is used as a P2SH redeem script, hashed again
+	 * and encoded with the network's script-hash prefix.  See:
+	 *     https://github.com/bitcoin/bips/blob/master/bip-0141.mediawiki#p2wpkh-nested-in-bip16-p2sh
+	 */
+	redeemScript := kmdutil.P2WPKHScript(publicKeyHash)
+	fmt.Printf("P2SH-P2WPKH redeem script: %x\n", redeemScript)
+	for _, segwitNet := range segwitNets[:2] {
+		nestedAddress, err := kmdutil.P2SHP2WPKHAddress(serializedPublicKey, segwitNet)
+		if err != nil {
+			fmt.Println("P2SH-P2WPKH address error:", err)
+			continue
+		}
+		fmt.Printf("P2SH-P2WPKH address (%s): %s\n", segwitNet, nestedAddress)
+	}
+	fmt.Println()
 
 	/*
-	 * WIF Key (Compressed) need extra byte added to the end
-	 * along with version + privkey hash + 01
+	 * Taproot (P2TR) key-path address: the x-only public key is tweaked
+	 * with its TapTweak tagged hash, Q = P + t*G, and the x coordinate of
+	 * Q is encoded as a witness v1 program in Bech32m.  See:
+	 *     https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+	 */
+	outputKey, err := kmdutil.TaprootOutputKey(serializedPublicKey, nil)
+	if err != nil {
+		fmt.Println("P2TR output key error:", err)
+	} else {
+		fmt.Printf("P2TR internal key: %x\n", serializedPublicKey[1:])
+		fmt.Printf("P2TR output key: %x\n", outputKey.SerializeXOnly())
+	}
+	for _, segwitNet := range segwitNets[:2] {
+		taprootAddress, err := kmdutil.P2TRAddress(serializedPublicKey, nil, segwitNet)
+		if err != nil {
+			fmt.Println("P2TR address error:", err)
+			continue
+		}
+		fmt.Printf("P2TR address (%s): %s\n", segwitNet, taprootAddress)
+	}
+	tweakedPrivKey, err := kmdutil.TaprootTweakPrivKey(privKey.Int(), nil)
+	if err != nil {
+		fmt.Println("P2TR private key tweak error:", err)
+	} else {
+		fmt.Printf("P2TR tweaked private key: %064x\n", tweakedPrivKey)
+	}
+	fmt.Println()
+
+	/*
+	 * Wallet Import Format: the version byte and the 32-byte key in
+	 * Base58Check, with an extra 0x01 byte before the checksum when the
+	 * key is used with a compressed public key.  See:
+	 *	https://www.mobilefish.com/services/cryptocurrency/cryptocurrency.html#refPrivateKeyHex
+	 *     https://en.bitcoin.it/wiki/Wallet_import_format
 	 */
-	byteone := []byte{0x01}                                  // Extra byte 01 to add to PrivKey at the end
-	privKeyVerByte := append(privKeyPlusVersion, byteone...) // privkey version + privkey hash + extra byte
-	privKeyChecksumComp := s256(s256(privKeyVerByte))[:4]    // first 4 bytes of double hashed (privkey version + privkey hash + extra byte)
-	fmt.Printf("byteone: %d\n", byteone)
-	fmt.Printf("bitcoin version + private key hash + 01 byte: %x   byte length: %d\n", privKeyVerByte, len(privKeyVerByte))
-	fmt.Printf("privKey Checksum for Compressed WIF: %x\n", privKeyChecksumComp)
-
-	privKeyAddChecksum := append(privKeyVerByte, privKeyChecksumComp...) // privkey version + privkey hash + extra byte + last
-	fmt.Printf("Privkey version + privkey hash + extra byte + Compressed Checksum: %x   byte length: %d\n", privKeyAddChecksum, len(privKeyAddChecksum))
-
-	wifCompressed := base58(privKeyAddChecksum)
-	fmt.Println("WIF (Compressed):", wifCompressed, "\n")
+	fmt.Printf("Private Key version byte: %d\n", []byte{net.PrivateKeyID})
+	fmt.Println("WIF of the addresses above:", privKey.WIF(net, compressed))
+	fmt.Println()
+	fmt.Println("WIF (Uncompressed):", privKey.WIF(net, false), "\n")
+	fmt.Println("WIF (Compressed):", privKey.WIF(net, true), "\n")
 }
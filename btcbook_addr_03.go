@@ -10,8 +10,11 @@ package main
 import (
 	"crypto/sha256"
 	"fmt"
+	"log"
 	"math/big"
+	"os"
 
+	"btc-practice/kmdutil"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -134,33 +137,6 @@ func s256(data []byte) []byte {
 	return h.Sum(nil)
 }
 
-/*
- * Encode the data in Bitcoin's Base58 format.  See:
- *     https://en.bitcoin.it/wiki/Base58Check_encoding#Base58_symbol_chart
- */
-func base58(data []byte) string {
-	zero := big.NewInt(0)
-	base := big.NewInt(58)
-	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZ" +
-		"abcdefghijkmnopqrstuvwxyz"
-
-	var s []byte
-	x := new(big.Int).SetBytes(data)
-	r := new(big.Int)
-	for x.Cmp(zero) != 0 {
-		x.DivMod(x, base, r)
-		s = append(s, alphabet[r.Int64()])
-	}
-	for i := 0; data[i] == 0; i++ {
-		s = append(s, alphabet[0])
-	}
-	// Reverse the array.
-	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-		s[i], s[j] = s[j], s[i]
-	}
-	return string(s)
-}
-
 func main() {
 	fmt.Println("Example from Mastering Bitcoin, pages 69-70.")
 
@@ -195,6 +171,21 @@ func main() {
 	// with:
 	privateKey.SetString("038109007313a5807b2eccc082c8c3fbb988a973"+
 		"cacf1a7df9ce725c31b14776", 16)
+
+	/*
+	 * A WIF given on the command line replaces the book's key.  Its
+	 * version byte selects the network, and an uncompressed WIF (5...
+	 * or 7...) the uncompressed public key, e.g.
+	 *     go run btcbook_addr_03.go 5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ
+	 */
+	net, compressed := kmdutil.BTCMainnet, true
+	if len(os.Args) > 1 {
+		wifKey, wifNet, wifCompressed, err := kmdutil.DecodeWIF(os.Args[1])
+		if err != nil {
+			log.Fatalf("WIF decode error: %v", err)
+		}
+		privateKey, net, compressed = wifKey, wifNet, wifCompressed
+	}
 	fmt.Println("\tprivateKey:")
 	fmt.Println(privateKey)
 
@@ -208,6 +199,9 @@ func main() {
 	fmt.Println(publicKey)
 
 	serializedPublicKey := publicKey.Serialize()
+	if !compressed {
+		serializedPublicKey = kmdutil.Point{X: publicKey.x, Y: publicKey.y}.SerializeUncompressed()
+	}
 	fmt.Println("\tserializedPublicKey:")
 	fmt.Printf("%x\n", serializedPublicKey)
 
@@ -221,7 +215,7 @@ func main() {
 	 *     Mastering Bitcoin, page 58
 	 *     https://en.bitcoin.it/wiki/Technical_background_of_version_1_Bitcoin_addresses#How_to_create_Bitcoin_Address - Steps 5-7.
 	 */
-	version := []byte{0}
+	version := append([]byte{}, net.PubKeyHashAddrID...)
 	versionPlusHash := append(version, publicKeyHash...)
 	checksum := s256(s256(versionPlusHash))[:4]
 	fmt.Println("\tchecksum:")
@@ -232,7 +226,7 @@ func main() {
 	 * Bitcoin's Base58Check format.  See:
 	 *     Mastering Bitcoin, page 66.
 	 */
-	address := base58(append(versionPlusHash, checksum...))
+	address := kmdutil.Base58Encode(append(versionPlusHash, checksum...))
 	fmt.Println("\taddress:")
 	fmt.Println(address)
 }
@@ -2,4 +2,7 @@ module btc-practice
 
 go 1.16
 
-require golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+require (
+	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+	golang.org/x/text v0.3.6
+)
@@ -0,0 +1,80 @@
+package main
+
+// BIP44/49/84/86 account table practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+//
+//	go run hd_accounts.go                     # kmd, BIP39 test mnemonic
+//	go run hd_accounts.go btc "<mnemonic>" [passphrase]
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+const addressCount = 3
+
+func main() {
+	netName := "kmd"
+	mnemonic := "abandon abandon abandon abandon abandon abandon " +
+		"abandon abandon abandon abandon abandon about"
+	passphrase := ""
+	if len(os.Args) > 1 {
+		netName = os.Args[1]
+	}
+	if len(os.Args) > 2 {
+		mnemonic = os.Args[2]
+	}
+	if len(os.Args) > 3 {
+		passphrase = os.Args[3]
+	}
+
+	net, err := kmdutil.NetworkByName(netName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	seed, err := kmdutil.MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+	master, err := kmdutil.NewMasterKey(seed, net)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("network:", net, " coin type:", net.HDCoinType)
+	fmt.Println("mnemonic:", mnemonic)
+
+	/*
+	 * One account per purpose.  The purpose fixes the address type of
+	 * every key below it, so chains without SegWit only get BIP44.
+	 */
+	for _, p := range kmdutil.Purposes {
+		account, err := master.DeriveAccount(p, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("\n%s  %s  (%s)\n", p, kmdutil.AccountPath(p, net, 0), p.AddressType())
+		// SLIP-132 only has versions for SegWit on chains that support
+		// it, so BIP86 accounts and every KMD account print as an xpub.
+		fmt.Println("  account key:", account.Neuter())
+		for _, change := range []bool{false, true} {
+			addrs, err := kmdutil.AccountAddresses(account, p, change, 0, addressCount)
+			if errors.Is(err, kmdutil.ErrNoSegWit) {
+				fmt.Println("  skipped:", err)
+				break
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, a := range addrs {
+				fmt.Printf("  %-22s %-64s %s\n", a.Path, a.Address, a.WIF)
+			}
+		}
+	}
+}
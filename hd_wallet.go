@@ -0,0 +1,117 @@
+package main
+
+// BIP32 hierarchical deterministic wallet practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+//
+//	go run hd_wallet.go                             # new 12-word mnemonic
+//	go run hd_wallet.go "<mnemonic>" "m/44'/141'/0'/0/5" [passphrase]
+//	go run hd_wallet.go <seed hex> "m/44'/141'/0'/0/5"
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	var mnemonic, seedHex, passphrase string
+	path := "m/44'/141'/0'/0/5"
+	if len(os.Args) > 1 {
+		if strings.Contains(os.Args[1], " ") {
+			mnemonic = os.Args[1]
+		} else {
+			seedHex = os.Args[1]
+		}
+	}
+	if len(os.Args) > 2 {
+		path = os.Args[2]
+	}
+	if len(os.Args) > 3 {
+		passphrase = os.Args[3]
+	}
+
+	/*
+	 * The seed comes from a BIP39 mnemonic, stretched together with the
+	 * optional passphrase, unless a raw seed is given in hex.
+	 */
+	var seed []byte
+	var err error
+	if seedHex == "" {
+		if mnemonic == "" {
+			if mnemonic, err = kmdutil.GenerateMnemonic(12, nil); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Println("mnemonic:", mnemonic)
+		if seed, err = kmdutil.MnemonicToSeed(mnemonic, passphrase); err != nil {
+			log.Fatal(err)
+		}
+		seedHex = hex.EncodeToString(seed)
+	} else if seed, err = hex.DecodeString(seedHex); err != nil {
+		log.Fatal(err)
+	}
+
+	master, err := kmdutil.NewMasterKey(seed, kmdutil.KMD)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("seed:", seedHex)
+	fmt.Printf("master fingerprint: %x\n", master.Fingerprint())
+	fmt.Println("m xprv:", master)
+	fmt.Println("m xpub:", master.Neuter())
+
+	/*
+	 * Walk the path one level at a time, so that each step's parent
+	 * fingerprint and child number can be seen.
+	 */
+	indexes, err := kmdutil.ParseDerivationPath(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	k := master
+	for n, i := range indexes {
+		if k, err = k.Child(i); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("\n%s  depth %d  parent %x  child %d\n",
+			kmdutil.FormatDerivationPath(indexes[:n+1]), k.Depth, k.ParentFingerprint, k.ChildNumber)
+		fmt.Println("  xprv:", k)
+		fmt.Println("  xpub:", k.Neuter())
+	}
+
+	privKey, err := k.PrivateKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("\nWIF:", privKey.WIF(kmdutil.KMD, true))
+	fmt.Println("address:", kmdutil.P2PKHAddress(k.PubKey(), kmdutil.KMD))
+
+	/*
+	 * The non-hardened tail of the path can also be derived from the
+	 * account xpub alone (CKDpub), giving the same address without any
+	 * private key.
+	 */
+	if len(indexes) >= 2 && indexes[len(indexes)-1] < kmdutil.HardenedKeyStart &&
+		indexes[len(indexes)-2] < kmdutil.HardenedKeyStart {
+		account, err := master.Derive(kmdutil.FormatDerivationPath(indexes[:len(indexes)-2]))
+		if err != nil {
+			log.Fatal(err)
+		}
+		xpub, err := kmdutil.ParseExtendedKey(account.Neuter().String())
+		if err != nil {
+			log.Fatal(err)
+		}
+		pub, err := xpub.Derive(kmdutil.FormatDerivationPath(indexes[len(indexes)-2:]))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("address from xpub:", kmdutil.P2PKHAddress(pub.PubKey(), kmdutil.KMD))
+	}
+}
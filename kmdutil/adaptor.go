@@ -0,0 +1,170 @@
+package kmdutil
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Schnorr adaptor signatures.  A pre-signature made under an adaptor
+// point T = t*G can only be turned into a valid BIP340 signature by
+// someone who knows t, and anyone holding both the pre-signature and the
+// completed signature learns t.  This is the building block of
+// scriptless-script atomic swaps.  See:
+//
+//	https://github.com/BlockstreamResearch/scriptless-scripts/blob/master/md/atomic-swap.md
+//	https://bitcoinops.org/en/topics/adaptor-signatures/
+//
+// The final nonce of the completed signature is R + T, where R = k*G is
+// the signer's own nonce.  BIP340 requires the final nonce to have an even
+// y coordinate; when R + T is odd the signer negates k instead, and the
+// completion and extraction steps subtract t rather than add it.  The
+// parity is carried in the prefix byte of the serialized nonce.
+
+var (
+	errInvalidAdaptorLen   = errors.New("invalid adaptor signature length")
+	errInvalidAdaptorPoint = errors.New("invalid adaptor point")
+	errInvalidAdaptorS     = errors.New("adaptor signature scalar out of range")
+	errAdaptorMismatch     = errors.New("signature does not match the adaptor signature")
+)
+
+// AdaptorSignature is a Schnorr pre-signature.  R is the final nonce
+// R + T and S is the pre-signature scalar s' = k + e*d.
+type AdaptorSignature struct {
+	R Point
+	S *big.Int
+}
+
+// validAdaptorPoint reports whether T is a usable adaptor or nonce point:
+// set, not the point at infinity and on the curve.  The zero Point has nil
+// coordinates.
+func validAdaptorPoint(T Point) bool {
+	return T.X != nil && T.Y != nil && !T.IsInfinity() && T.IsOnCurve()
+}
+
+// AdaptorSign creates a pre-signature of msg with the private key d under
+// the adaptor point T.  auxRand must be 32 bytes of fresh randomness.
+func AdaptorSign(d *big.Int, msg []byte, T Point, auxRand []byte) (*AdaptorSignature, error) {
+	if d == nil || !validPrivKey(d) {
+		return nil, errInvalidPrivKey
+	}
+	if len(auxRand) != 32 {
+		return nil, errInvalidAuxLen
+	}
+	if !validAdaptorPoint(T) {
+		return nil, errInvalidAdaptorPoint
+	}
+	P := ECBaseMul(d)
+	dd := new(big.Int).Set(d)
+	if !P.HasEvenY() {
+		dd.Sub(curveN, dd)
+	}
+	px := P.SerializeXOnly()
+
+	k := schnorrNonce(dd, px, msg, auxRand, T.Serialize())
+	if k.Sign() == 0 {
+		return nil, errSignatureFailure
+	}
+	R := NewPoint().ECPointAdd(ECBaseMul(k), T)
+	if R.IsInfinity() {
+		return nil, errSignatureFailure
+	}
+	if !R.HasEvenY() {
+		k.Sub(curveN, k)
+	}
+	e := schnorrChallenge(R.SerializeXOnly(), px, msg)
+
+	// s' = k + e*d   mod n
+	s := new(big.Int).Mul(e, dd)
+	s.Add(s, k)
+	s.Mod(s, curveN)
+
+	sig := &AdaptorSignature{R, s}
+	if !sig.Verify(px, msg, T) {
+		return nil, errSignatureFailure
+	}
+	return sig, nil
+}
+
+// Verify checks that sig is a valid pre-signature of msg under the
+// adaptor point T for the 32-byte x-only public key pubKey, i.e. that it
+// completes to a valid BIP340 signature once t is known.
+func (sig *AdaptorSignature) Verify(pubKey, msg []byte, T Point) bool {
+	if len(pubKey) != 32 || sig.S == nil || sig.S.Sign() < 0 || sig.S.Cmp(curveN) >= 0 {
+		return false
+	}
+	if !validAdaptorPoint(sig.R) || !validAdaptorPoint(T) {
+		return false
+	}
+	P, err := liftX(new(big.Int).SetBytes(pubKey))
+	if err != nil {
+		return false
+	}
+	e := schnorrChallenge(sig.R.SerializeXOnly(), pubKey, msg)
+
+	// The signer's own nonce is R - T, negated when R has an odd y.
+	K := NewPoint().ECPointAdd(sig.R, T.Neg())
+	if !sig.R.HasEvenY() {
+		K = K.Neg()
+	}
+
+	// s'*G == K + e*P
+	eP := NewPoint().ECPointMul(e, P)
+	want := NewPoint().ECPointAdd(K, eP)
+	return ECBaseMul(sig.S).Equals(want)
+}
+
+// Complete turns the pre-signature into a 64-byte BIP340 signature using
+// the adaptor secret t.
+func (sig *AdaptorSignature) Complete(t *big.Int) []byte {
+	s := new(big.Int)
+	if sig.R.HasEvenY() {
+		s.Add(sig.S, t)
+	} else {
+		s.Sub(sig.S, t)
+	}
+	s.Mod(s, curveN)
+	return append(sig.R.SerializeXOnly(), bytes32(s)...)
+}
+
+// Extract recovers the adaptor secret t from a completed signature.
+func (sig *AdaptorSignature) Extract(final []byte) (*big.Int, error) {
+	if len(final) != 64 {
+		return nil, errInvalidSigLen
+	}
+	if sig.S == nil || !validAdaptorPoint(sig.R) {
+		return nil, errInvalidAdaptorPoint
+	}
+	if new(big.Int).SetBytes(final[:32]).Cmp(sig.R.X) != 0 {
+		return nil, errAdaptorMismatch
+	}
+	t := new(big.Int).SetBytes(final[32:])
+	if sig.R.HasEvenY() {
+		t.Sub(t, sig.S)
+	} else {
+		t.Sub(sig.S, t)
+	}
+	return t.Mod(t, curveN), nil
+}
+
+// Serialize encodes the pre-signature as the 33-byte compressed final
+// nonce followed by the 32-byte scalar.
+func (sig *AdaptorSignature) Serialize() []byte {
+	return append(sig.R.Serialize(), bytes32(sig.S)...)
+}
+
+// ParseAdaptorSignature decodes a 65-byte pre-signature produced by
+// Serialize.
+func ParseAdaptorSignature(b []byte) (*AdaptorSignature, error) {
+	if len(b) != 65 {
+		return nil, errInvalidAdaptorLen
+	}
+	R, err := ParsePubKey(b[:33])
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).SetBytes(b[33:])
+	if s.Cmp(curveN) >= 0 {
+		return nil, errInvalidAdaptorS
+	}
+	return &AdaptorSignature{R, s}, nil
+}
@@ -0,0 +1,79 @@
+package kmdutil
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestAdaptorSignature(t *testing.T) {
+	d := big.NewInt(0x1234567)
+	pub, err := SchnorrPubKey(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := bytes.Repeat([]byte{0x5a}, 32)
+	aux := make([]byte, 32)
+
+	// Several secrets, so that both parities of R + T are exercised.
+	for i := int64(1); i <= 8; i++ {
+		secret := new(big.Int).Mul(big.NewInt(i), big.NewInt(0x1e3779b97f4a7c15))
+		T := ECBaseMul(secret)
+		pre, err := AdaptorSign(d, msg, T, aux)
+		if err != nil {
+			t.Fatalf("%d: AdaptorSign: %v", i, err)
+		}
+		if !pre.Verify(pub, msg, T) {
+			t.Fatalf("%d: pre-signature does not verify", i)
+		}
+		parsed, err := ParseAdaptorSignature(pre.Serialize())
+		if err != nil || !parsed.Verify(pub, msg, T) {
+			t.Fatalf("%d: serialization round trip failed: %v", i, err)
+		}
+		final := pre.Complete(secret)
+		if !SchnorrVerify(pub, msg, final) {
+			t.Fatalf("%d: completed signature does not verify", i)
+		}
+		got, err := pre.Extract(final)
+		if err != nil {
+			t.Fatalf("%d: Extract: %v", i, err)
+		}
+		if got.Cmp(secret) != 0 {
+			t.Errorf("%d: extracted %x, want %x", i, got, secret)
+		}
+		if pre.Verify(pub, msg, ECBaseMul(big.NewInt(7))) {
+			t.Errorf("%d: pre-signature verifies under the wrong adaptor point", i)
+		}
+	}
+}
+
+func TestAdaptorInvalidInputs(t *testing.T) {
+	d := big.NewInt(0x1234567)
+	pub, _ := SchnorrPubKey(d)
+	msg := make([]byte, 32)
+	aux := make([]byte, 32)
+	T := ECBaseMul(big.NewInt(5))
+
+	if _, err := AdaptorSign(d, msg, Point{}, aux); err != errInvalidAdaptorPoint {
+		t.Errorf("AdaptorSign(zero point) error = %v, want %v", err, errInvalidAdaptorPoint)
+	}
+	if _, err := AdaptorSign(nil, msg, T, aux); err != errInvalidPrivKey {
+		t.Errorf("AdaptorSign(nil key) error = %v, want %v", err, errInvalidPrivKey)
+	}
+
+	pre, err := AdaptorSign(d, msg, T, aux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pre.Verify(pub, msg, Point{}) {
+		t.Error("Verify accepted a zero adaptor point")
+	}
+	for _, sig := range []*AdaptorSignature{{R: pre.R}, {S: pre.S}} {
+		if sig.Verify(pub, msg, T) {
+			t.Errorf("Verify accepted %+v", sig)
+		}
+		if _, err := sig.Extract(make([]byte, 64)); err == nil {
+			t.Errorf("Extract accepted %+v", sig)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package kmdutil
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrUnknownAddressPrefix is returned when no registered network has the
+// version prefix of a Base58Check address.
+var ErrUnknownAddressPrefix = errors.New("unknown address version prefix")
+
+// P2PKHAddress returns the legacy Base58Check address of a public key:
+// the network's pubkey-hash prefix followed by Hash160(pubKey).  See:
+//
+//	Mastering Bitcoin, page 66.
+func P2PKHAddress(pubKey []byte, net *Network) string {
+	return Base58CheckEncodePrefix(Hash160(pubKey), net.PubKeyHashAddrID)
+}
+
+// DecodeBase58Address decodes a P2PKH or P2SH address and finds its
+// network by the longest version prefix that leaves a 20-byte hash, so a
+// two-byte Zcash prefix wins over a one-byte prefix matching its first
+// byte.  Networks sharing a prefix, such as the Bitcoin test networks,
+// resolve to the one registered first.
+func DecodeBase58Address(addr string) (net *Network, scriptHash bool, hash []byte, err error) {
+	data, err := base58CheckDecode(addr, 1)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	best := 0
+	for _, n := range Networks() {
+		for _, p := range []struct {
+			prefix []byte
+			script bool
+		}{
+			{n.PubKeyHashAddrID, false},
+			{n.ScriptHashAddrID, true},
+		} {
+			if len(p.prefix) > best && len(data) == len(p.prefix)+20 &&
+				bytes.HasPrefix(data, p.prefix) {
+				net, scriptHash, best = n, p.script, len(p.prefix)
+			}
+		}
+	}
+	if net == nil {
+		return nil, false, nil, ErrUnknownAddressPrefix
+	}
+	return net, scriptHash, data[best:], nil
+}
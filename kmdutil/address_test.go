@@ -0,0 +1,72 @@
+package kmdutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeBase58Address(t *testing.T) {
+	tests := []struct {
+		addr       string
+		net        *Network
+		scriptHash bool
+		hash       string
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", BTCMainnet, false, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", BTCMainnet, true, "b472a266d0bd89c13706a4132ccfb16f7c3b9fcb"},
+		// The test networks share prefixes; testnet is registered first.
+		{"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", BTCTestnet, false, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", LTC, false, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+	}
+	for _, tt := range tests {
+		net, scriptHash, hash, err := DecodeBase58Address(tt.addr)
+		if err != nil || net != tt.net || scriptHash != tt.scriptHash || !bytes.Equal(hash, mustHex(t, tt.hash)) {
+			t.Errorf("DecodeBase58Address(%s) = %v, %v, %x, %v, want %v, %v, %s",
+				tt.addr, net, scriptHash, hash, err, tt.net, tt.scriptHash, tt.hash)
+		}
+	}
+}
+
+func TestDecodeBase58AddressRoundTrip(t *testing.T) {
+	// Every network, including the two-byte Zcash prefixes whose first
+	// byte is also a one-byte prefix elsewhere.  Networks sharing a
+	// prefix decode to the first of them, so compare prefixes.
+	h := mustHex(t, "751e76e8199196d454941c45d1b3a323f1433bd6")
+	prefixOf := func(net *Network, scriptHash bool) []byte {
+		if scriptHash {
+			return net.ScriptHashAddrID
+		}
+		return net.PubKeyHashAddrID
+	}
+	for _, want := range Networks() {
+		for _, script := range []bool{false, true} {
+			addr := Base58CheckEncodePrefix(h, prefixOf(want, script))
+			net, scriptHash, hash, err := DecodeBase58Address(addr)
+			if err != nil || scriptHash != script || !bytes.Equal(hash, h) ||
+				!bytes.Equal(prefixOf(net, scriptHash), prefixOf(want, script)) {
+				t.Errorf("DecodeBase58Address(%s) = %v, %v, %x, %v for %s", addr, net, scriptHash, hash, err, want)
+			}
+		}
+	}
+	if net, _, _, _ := DecodeBase58Address(Base58CheckEncodePrefix(h, ZEC.PubKeyHashAddrID)); net != ZEC {
+		t.Errorf("zcash t1 address resolved to %v", net)
+	}
+}
+
+func TestDecodeBase58AddressInvalid(t *testing.T) {
+	h := make([]byte, 20)
+	tests := []struct {
+		addr string
+		want error
+	}{
+		// A WIF has the right checksum but not a 20-byte hash.
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", ErrUnknownAddressPrefix},
+		{Base58CheckEncodePrefix(h, []byte{0xff}), ErrUnknownAddressPrefix},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", ErrChecksum},
+	}
+	for _, tt := range tests {
+		if _, _, _, err := DecodeBase58Address(tt.addr); err != tt.want {
+			t.Errorf("DecodeBase58Address(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+}
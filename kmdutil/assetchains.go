@@ -0,0 +1,348 @@
+package kmdutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Komodo smart chains (assetchains).  Every chain shares the KMD address
+// prefixes, but komodod derives its network magic, and from that its
+// ports, from the chain name and the ac_* parameters.  See:
+//
+//	https://github.com/KomodoPlatform/komodo/blob/master/src/assetchains.json
+//	https://github.com/KomodoPlatform/komodo/blob/master/src/komodo_utils.cpp (komodo_args)
+//
+// Only the parameters listed in assetChainParams are modelled.  A chain
+// with any other ac_* key, such as ac_founders, is skipped and reported
+// with UnsupportedParamError instead of getting a wrong magic.
+
+// AssetChain holds the ac_* parameters of one smart chain.  The per-era
+// slices have Eras entries.
+type AssetChain struct {
+	Name        string
+	Supply      uint64
+	Eras        int
+	End         []uint64
+	Reward      []uint64
+	Halving     []uint64
+	Decay       []uint64
+	NotaryPay   []uint64
+	Perc        uint64
+	PubKey      []byte
+	Staked      uint8
+	CC          uint16
+	Public      bool
+	Private     bool
+	TxPow       uint8
+	Script      []byte
+	CCLib       string
+	BlockTime   uint32
+	CBMaturity  uint32
+	AdaptivePow uint8
+}
+
+// assetChainParams lists the JSON keys understood by LoadAssetChains.
+// ac_sapling does not change the magic; keys without the ac_ prefix, such
+// as addnode, are ignored.
+var assetChainParams = map[string]bool{
+	"ac_name": true, "ac_supply": true, "ac_eras": true,
+	"ac_end": true, "ac_reward": true, "ac_halving": true, "ac_decay": true,
+	"ac_notarypay": true, "ac_perc": true, "ac_pubkey": true,
+	"ac_staked": true, "ac_cc": true, "ac_public": true, "ac_private": true,
+	"ac_txpow": true, "ac_script": true, "ac_cclib": true,
+	"ac_blocktime": true, "ac_cbmaturity": true, "ac_adaptivepow": true,
+	"ac_sapling": true,
+}
+
+// kmdMaxEras is komodod's ASSETCHAINS_MAX_ERAS.
+const kmdMaxEras = 7
+
+var errMissingACName = errors.New("assetchain without ac_name")
+
+// UnsupportedParamError is returned for an ac_* parameter that affects the
+// magic but is not modelled here.
+type UnsupportedParamError struct {
+	Chain, Param string
+}
+
+func (e UnsupportedParamError) Error() string {
+	return fmt.Sprintf("assetchain %s: unsupported parameter %s", e.Chain, e.Param)
+}
+
+// AssetParamError is returned for a parameter value that cannot be parsed.
+type AssetParamError struct {
+	Chain, Param, Value string
+}
+
+func (e AssetParamError) Error() string {
+	return fmt.Sprintf("assetchain %s: invalid %s value %q", e.Chain, e.Param, e.Value)
+}
+
+// LoadAssetChains parses an assetchains.json array.  Values may be JSON
+// strings, as in the upstream file, or numbers.  Chains with an
+// unmodelled parameter are left out of chains and listed in skipped, so
+// the upstream file loads; any other error fails the whole load.
+func LoadAssetChains(r io.Reader) (chains []*AssetChain, skipped []UnsupportedParamError, err error) {
+	var entries []map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, nil, err
+	}
+	chains = make([]*AssetChain, 0, len(entries))
+	for _, entry := range entries {
+		ac, err := parseAssetChain(entry)
+		if e, ok := err.(UnsupportedParamError); ok {
+			skipped = append(skipped, e)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		chains = append(chains, ac)
+	}
+	return chains, skipped, nil
+}
+
+func parseAssetChain(entry map[string]json.RawMessage) (*AssetChain, error) {
+	params := map[string]string{}
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "ac_") {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(entry[k], &s); err != nil {
+			// Accept bare numbers as well as strings.
+			s = string(entry[k])
+		}
+		params[k] = s
+	}
+
+	ac := &AssetChain{Name: params["ac_name"], Supply: 10, Eras: 1, BlockTime: 60}
+	if ac.Name == "" {
+		return nil, errMissingACName
+	}
+	for _, k := range keys {
+		if strings.HasPrefix(k, "ac_") && !assetChainParams[k] {
+			return nil, UnsupportedParamError{ac.Name, k}
+		}
+	}
+
+	var err error
+	uintParam := func(key string, bits int) uint64 {
+		s, ok := params[key]
+		if !ok || err != nil {
+			return 0
+		}
+		v, perr := strconv.ParseUint(s, 10, bits)
+		if perr != nil {
+			err = AssetParamError{ac.Name, key, s}
+		}
+		return v
+	}
+	// eraParam parses a comma separated per-era list.  Like komodod's
+	// Split, missing trailing eras repeat the last value given.
+	eraParam := func(key string) []uint64 {
+		out := make([]uint64, ac.Eras)
+		s, ok := params[key]
+		if !ok || err != nil {
+			return out
+		}
+		fields := strings.Split(s, ",")
+		for i := range out {
+			if i >= len(fields) {
+				out[i] = out[i-1]
+				continue
+			}
+			v, perr := strconv.ParseUint(strings.TrimSpace(fields[i]), 10, 64)
+			if perr != nil {
+				err = AssetParamError{ac.Name, key, s}
+				return out
+			}
+			out[i] = v
+		}
+		return out
+	}
+
+	if _, ok := params["ac_supply"]; ok {
+		ac.Supply = uintParam("ac_supply", 64)
+	}
+	if _, ok := params["ac_eras"]; ok {
+		ac.Eras = int(uintParam("ac_eras", 8))
+		if err == nil && (ac.Eras < 1 || ac.Eras > kmdMaxEras) {
+			err = AssetParamError{ac.Name, "ac_eras", params["ac_eras"]}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	ac.End = eraParam("ac_end")
+	ac.Reward = eraParam("ac_reward")
+	ac.Halving = eraParam("ac_halving")
+	ac.Decay = eraParam("ac_decay")
+	ac.NotaryPay = eraParam("ac_notarypay")
+	for i, h := range ac.Halving {
+		// komodod raises short halving intervals to one day.
+		if h != 0 && h < 1440 {
+			ac.Halving[i] = 1440
+		}
+	}
+	ac.Perc = uintParam("ac_perc", 64)
+	ac.Staked = uint8(uintParam("ac_staked", 8))
+	ac.CC = uint16(uintParam("ac_cc", 16))
+	ac.Public = uintParam("ac_public", 64) != 0
+	ac.Private = uintParam("ac_private", 64) != 0
+	ac.TxPow = uint8(uintParam("ac_txpow", 8))
+	ac.CBMaturity = uint32(uintParam("ac_cbmaturity", 32))
+	ac.AdaptivePow = uint8(uintParam("ac_adaptivepow", 8))
+	if _, ok := params["ac_blocktime"]; ok {
+		ac.BlockTime = uint32(uintParam("ac_blocktime", 32))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := params["ac_pubkey"]; ok {
+		ac.PubKey, err = hex.DecodeString(s)
+		if err != nil || len(ac.PubKey) != 33 {
+			return nil, AssetParamError{ac.Name, "ac_pubkey", s}
+		}
+	}
+	if s, ok := params["ac_script"]; ok {
+		ac.Script, err = hex.DecodeString(s)
+		if err != nil {
+			return nil, AssetParamError{ac.Name, "ac_script", s}
+		}
+	}
+	ac.CCLib = params["ac_cclib"]
+	return ac, nil
+}
+
+// extraData returns the parameter blob that komodod hashes into the magic,
+// or nil for a chain with default parameters.  ac_cc and ac_staked alone
+// do not count as extra parameters.
+func (ac *AssetChain) extraData() []byte {
+	lastEra := ac.Eras - 1
+	if ac.End[0] == 0 && ac.Reward[0] == 0 && ac.Halving[0] == 0 &&
+		ac.Decay[0] == 0 && ac.NotaryPay[0] == 0 && ac.Perc == 0 &&
+		!ac.Public && !ac.Private && ac.TxPow == 0 && len(ac.Script) == 0 &&
+		ac.PubKey == nil && lastEra == 0 && ac.CCLib == "" &&
+		ac.BlockTime == 60 && ac.CBMaturity == 0 && ac.AdaptivePow == 0 {
+		return nil
+	}
+
+	le64 := func(b []byte, v uint64) []byte {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], v)
+		return append(b, buf[:]...)
+	}
+	le32 := func(b []byte, v uint32) []byte {
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], v)
+		return append(b, buf[:]...)
+	}
+
+	extra := make([]byte, 33, 256)
+	copy(extra, ac.PubKey)
+	for i := 0; i <= lastEra; i++ {
+		extra = le64(extra, ac.End[i])
+		extra = le64(extra, ac.Reward[i])
+		extra = le64(extra, ac.Halving[i])
+		extra = le64(extra, ac.Decay[i])
+		if ac.NotaryPay[0] != 0 {
+			extra = le64(extra, ac.NotaryPay[i])
+		}
+	}
+	if lastEra > 0 {
+		extra = le32(extra, uint32(lastEra))
+	}
+
+	val := ac.Perc | uint64(ac.Staked)<<32 | uint64(ac.CC)<<40 | uint64(ac.TxPow)
+	if ac.Public {
+		val |= 1 << 7
+	}
+	if ac.Private {
+		val |= 1 << 6
+	}
+	extra = le64(extra, val)
+
+	extra = append(extra, ac.Script...)
+	if len(ac.CCLib) > 1 {
+		extra = append(extra, ac.CCLib...)
+	}
+	if ac.BlockTime != 60 {
+		extra = le32(extra, ac.BlockTime)
+	}
+	if ac.CBMaturity != 0 {
+		extra = le32(extra, ac.CBMaturity)
+	}
+	if ac.AdaptivePow != 0 {
+		extra = append(extra, ac.AdaptivePow)
+	}
+	return extra
+}
+
+// Magic returns the network magic komodod derives for the chain:
+// crc32(le64(supply) || name), seeded with the first word of
+// sha256(extra) when the chain has extra parameters.
+func (ac *AssetChain) Magic() uint32 {
+	var crc0 uint32
+	if extra := ac.extraData(); extra != nil {
+		h := sha256.Sum256(extra)
+		crc0 = binary.LittleEndian.Uint32(h[:4])
+	}
+	buf := make([]byte, 8, 8+len(ac.Name))
+	binary.LittleEndian.PutUint64(buf, ac.Supply)
+	buf = append(buf, ac.Name...)
+	return crc32.Update(crc0, crc32.IEEETable, buf)
+}
+
+// P2PPort returns the default P2P port.  The RPC port is one above it.
+func (ac *AssetChain) P2PPort() uint16 {
+	magic := ac.Magic()
+	if ac.extraData() == nil {
+		return uint16(8000 + magic%7777)
+	}
+	return uint16(16000 + magic%49500)
+}
+
+// Network returns a Network with the KMD prefixes and the chain's magic
+// and ports.  The registry name is the lower case chain name.
+func (ac *AssetChain) Network() *Network {
+	net := *KMD
+	net.Name = strings.ToLower(ac.Name)
+	net.NetMagic = ac.Magic()
+	net.P2PPort = ac.P2PPort()
+	net.RPCPort = net.P2PPort + 1
+	return &net
+}
+
+// RegisterAssetChains loads assetchains.json from r and registers every
+// chain.  It returns the registered networks in file order and the chains
+// skipped by LoadAssetChains.
+func RegisterAssetChains(r io.Reader) (nets []*Network, skipped []UnsupportedParamError, err error) {
+	chains, skipped, err := LoadAssetChains(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	nets = make([]*Network, 0, len(chains))
+	for _, ac := range chains {
+		net := ac.Network()
+		if err := RegisterNetwork(net); err != nil {
+			return nil, nil, err
+		}
+		nets = append(nets, net)
+	}
+	return nets, skipped, nil
+}
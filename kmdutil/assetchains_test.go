@@ -0,0 +1,81 @@
+package kmdutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testAssetChains is the subset of komodod's assetchains.json in the demo
+// file, with ac_supply of ILN as a bare number, plus a chain with
+// parameters that are not modelled.
+const testAssetChains = `[
+  {"ac_name": "REVS", "ac_supply": "1300000", "addnode": ["seed1.kmd.sh"]},
+  {"ac_name": "SUPERNET", "ac_supply": "816061"},
+  {"ac_name": "ILN", "ac_supply": 10000000000, "ac_cc": "2"},
+  {"ac_name": "FOUNDERS", "ac_supply": "1000", "ac_founders": "1", "ac_ccenable": "228"},
+  {"ac_name": "RICK", "ac_supply": "90000000000", "ac_reward": "100000000", "ac_cc": "3", "ac_staked": "10"},
+  {"ac_name": "MORTY", "ac_supply": "90000000000", "ac_reward": "100000000", "ac_cc": "3", "ac_staked": "10"}
+]`
+
+func TestAssetChainMagic(t *testing.T) {
+	// The ports are those komodod listens on; the magic is what it logs.
+	tests := []struct {
+		name    string
+		magic   uint32
+		p2pPort uint16
+	}{
+		{"REVS", 0x4141771a, 10195},
+		{"SUPERNET", 0xb9112456, 11340},
+		{"ILN", 0x23cbb4fe, 12985},
+		{"RICK", 0xfd750df6, 25434},
+		{"MORTY", 0x83f3bcaf, 16347},
+	}
+	chains, skipped, err := LoadAssetChains(strings.NewReader(testAssetChains))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != len(tests) {
+		t.Fatalf("LoadAssetChains returned %d chains, want %d", len(chains), len(tests))
+	}
+	if want := []UnsupportedParamError{{"FOUNDERS", "ac_ccenable"}}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("LoadAssetChains skipped %v, want %v", skipped, want)
+	}
+	for i, tt := range tests {
+		ac := chains[i]
+		if ac.Name != tt.name {
+			t.Errorf("chain %d name = %s, want %s", i, ac.Name, tt.name)
+			continue
+		}
+		if got := ac.Magic(); got != tt.magic {
+			t.Errorf("%s magic = %#x, want %#x", tt.name, got, tt.magic)
+		}
+		net := ac.Network()
+		if net.NetMagic != tt.magic || net.P2PPort != tt.p2pPort || net.RPCPort != tt.p2pPort+1 {
+			t.Errorf("%s network magic %#x ports %d/%d, want %#x %d/%d", tt.name,
+				net.NetMagic, net.P2PPort, net.RPCPort, tt.magic, tt.p2pPort, tt.p2pPort+1)
+		}
+		if net.Name != strings.ToLower(tt.name) || net.PrivateKeyID != KMD.PrivateKeyID ||
+			string(net.PubKeyHashAddrID) != string(KMD.PubKeyHashAddrID) {
+			t.Errorf("%s network %+v does not carry the KMD prefixes", tt.name, net)
+		}
+	}
+}
+
+func TestLoadAssetChainsInvalid(t *testing.T) {
+	tests := []struct {
+		json string
+		want error
+	}{
+		{`[{"ac_supply": "100"}]`, errMissingACName},
+		{`[{"ac_name": "X", "ac_supply": "-1"}]`, AssetParamError{"X", "ac_supply", "-1"}},
+		{`[{"ac_name": "X", "ac_eras": "8"}]`, AssetParamError{"X", "ac_eras", "8"}},
+		{`[{"ac_name": "X", "ac_eras": "2", "ac_reward": "1,x"}]`, AssetParamError{"X", "ac_reward", "1,x"}},
+	}
+	for _, tt := range tests {
+		_, _, err := LoadAssetChains(strings.NewReader(tt.json))
+		if err != tt.want {
+			t.Errorf("LoadAssetChains(%s) error = %v, want %v", tt.json, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Base58Alphabet is a Base58 digit set.  Bitcoin, Ripple and Flickr use
+// the same 58 characters in different orders.
+type Base58Alphabet struct {
+	chars string
+	// index maps an input character to its value in chars, or 0xff
+	// when the character is not part of the alphabet.
+	index [256]byte
+	// pairs holds the two digits of every value below 58^2, so that the
+	// encoder divides once per two digits.
+	pairs [58 * 58][2]byte
+}
+
+// Built-in alphabets.  See:
+//
+//	https://en.bitcoin.it/wiki/Base58Check_encoding#Base58_symbol_chart
+//	https://xrpl.org/base58-encodings.html
+var (
+	BitcoinAlphabet = MustBase58Alphabet("123456789ABCDEFGHJKLMNPQRSTUVWXYZ" +
+		"abcdefghijkmnopqrstuvwxyz")
+	RippleAlphabet = MustBase58Alphabet("rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ" +
+		"2bcdeCg65jkm8oFqi1tuvAxyz")
+	FlickrAlphabet = MustBase58Alphabet("123456789abcdefghijkmnopqrstuvwxyz" +
+		"ABCDEFGHJKLMNPQRSTUVWXYZ")
+)
+
+// ErrBase58Alphabet is returned by NewBase58Alphabet for a string that is
+// not 58 distinct characters.
+var ErrBase58Alphabet = errors.New("base58 alphabet must be 58 distinct characters")
+
+// NewBase58Alphabet returns the alphabet whose digits 0..57 are the bytes
+// of chars.
+func NewBase58Alphabet(chars string) (*Base58Alphabet, error) {
+	if len(chars) != 58 {
+		return nil, ErrBase58Alphabet
+	}
+	a := &Base58Alphabet{chars: chars}
+	for i := range a.index {
+		a.index[i] = 0xff
+	}
+	for i := 0; i < len(chars); i++ {
+		if a.index[chars[i]] != 0xff {
+			return nil, ErrBase58Alphabet
+		}
+		a.index[chars[i]] = byte(i)
+	}
+	for i := range a.pairs {
+		a.pairs[i] = [2]byte{chars[i/58], chars[i%58]}
+	}
+	return a, nil
+}
+
+// MustBase58Alphabet is NewBase58Alphabet that panics on error, for
+// package-level alphabets.
+func MustBase58Alphabet(chars string) *Base58Alphabet {
+	a, err := NewBase58Alphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String returns the 58 characters of the alphabet.
+func (a *Base58Alphabet) String() string {
+	return a.chars
+}
+
+// InvalidCharacterError is returned when a Base58 string contains a
+// character outside the alphabet.  Pos is the byte offset of the
+// offending character.
+type InvalidCharacterError struct {
+	Pos  int
+	Char byte
+}
+
+func (e InvalidCharacterError) Error() string {
+	return fmt.Sprintf("invalid base58 character %q at position %d", e.Char, e.Pos)
+}
+
+// Base58Decode decodes a string in Bitcoin's Base58 alphabet.
+func Base58Decode(s string) ([]byte, error) {
+	return BitcoinAlphabet.Decode(s)
+}
+
+// Decode decodes a Base58 string.  Every leading zero digit ('1' for
+// Bitcoin, 'r' for Ripple) becomes a 0x00 byte, mirroring how the encoder
+// writes leading zero bytes.
+func (a *Base58Alphabet) Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == a.chars[0] {
+		zeros++
+	}
+
+	// Each base58 digit carries log(58)/log(256) ~= 0.733 bytes.
+	size := (len(s)-zeros)*733/1000 + 1
+	out := make([]byte, size)
+	high := size - 1 // out[high+1:] holds the value, out[high] is the first unused byte
+	for i := zeros; i < len(s); i++ {
+		c := a.index[s[i]]
+		if c == 0xff {
+			return nil, InvalidCharacterError{i, s[i]}
+		}
+		// out = out*58 + c
+		carry := uint32(c)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 58 * uint32(out[j])
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		high = j
+	}
+
+	// Skip the unused leading bytes of the big-endian result.
+	start := 0
+	for start < size && out[start] == 0 {
+		start++
+	}
+	return append(make([]byte, zeros, zeros+size-start), out[start:]...), nil
+}
+
+// base58Radix is 58^10, the largest power of 58 below 2^64.  The encoder
+// keeps its value in limbs of ten base58 digits each.
+const (
+	base58Radix       = 430804206899405824
+	base58LimbDigits  = 10
+	base58PairRadix   = 58 * 58
+	base58QuadRadix   = 58 * 58 * 58 * 58
+	base58StepBytes   = 7 // input bytes per limb update
+	base58StackDigits = 64
+)
+
+// The limb update divides a 128-bit value by the constant 58^10.  A
+// hardware 128-bit division is slow, so it multiplies by a precomputed
+// reciprocal of the normalized divisor instead.  See:
+//
+//	https://gmplib.org/~tege/division-paper.pdf - Algorithm 4.
+const (
+	base58RadixShift = 5 // leading zero bits of 58^10
+	base58RadixNorm  = base58Radix << base58RadixShift
+)
+
+// base58RadixInv is floor((2^128-1) / base58RadixNorm) - 2^64.
+var base58RadixInv, _ = bits.Div64(^uint64(base58RadixNorm), ^uint64(0), base58RadixNorm)
+
+// divRadix returns the quotient and remainder of hi:lo / 58^10, where hi
+// must be below 58^10.
+func divRadix(hi, lo uint64) (q, r uint64) {
+	u1 := hi<<base58RadixShift | lo>>(64-base58RadixShift)
+	u0 := lo << base58RadixShift
+	q1, q0 := bits.Mul64(base58RadixInv, u1)
+	var c uint64
+	q0, c = bits.Add64(q0, u0, 0)
+	q1 += u1 + c + 1
+	r = u0 - q1*base58RadixNorm
+	if r > q0 {
+		q1--
+		r += base58RadixNorm
+	}
+	if r >= base58RadixNorm {
+		q1++
+		r -= base58RadixNorm
+	}
+	return q1, r >> base58RadixShift
+}
+
+// putPair writes the two digits of v < 58^2 to o.
+func (a *Base58Alphabet) putPair(o []byte, v uint32) {
+	p := &a.pairs[v]
+	o[0], o[1] = p[0], p[1]
+}
+
+// Base58Encode encodes b in Bitcoin's Base58 format.
+func Base58Encode(b []byte) string {
+	return BitcoinAlphabet.Encode(b)
+}
+
+// Encode encodes b in Base58.  Every leading 0x00 byte becomes a leading
+// zero digit.  The conversion works on the bytes directly: the input is
+// consumed seven bytes at a time into little-endian limbs of ten base58
+// digits each, using 128-bit intermediate products, so no big.Int is
+// needed.  The digits are then written back to front into one buffer, so
+// no reverse pass is needed.
+func (a *Base58Alphabet) Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	rest := b[zeros:]
+
+	// Each input byte needs log(256)/log(58) ~= 1.366 base58 digits.
+	var limbStack [8]uint64
+	limbs := limbStack[:0]
+	if n := len(rest)*1366/1000/base58LimbDigits + 1; n > len(limbStack) {
+		limbs = make([]uint64, 0, n)
+	}
+	for len(rest) > 0 {
+		n := base58StepBytes
+		if len(rest) < n {
+			n = len(rest)
+		}
+		var carry uint64
+		for _, c := range rest[:n] {
+			carry = carry<<8 | uint64(c)
+		}
+		rest = rest[n:]
+
+		// limbs = limbs*256^n + carry.  A limb is below 2^59, so the high
+		// word of limb<<(8n) stays below the radix as divRadix requires.
+		shift := uint(8 * n)
+		for i, limb := range limbs {
+			hi, lo := limb>>(64-shift), limb<<shift
+			var c uint64
+			lo, c = bits.Add64(lo, carry, 0)
+			carry, limbs[i] = divRadix(hi+c, lo)
+		}
+		for carry != 0 {
+			limbs = append(limbs, carry%base58Radix)
+			carry /= base58Radix
+		}
+	}
+
+	// The digits are written back to front into a buffer sized for full
+	// limbs.  The most significant limb is written without its leading
+	// zero digits, the others are padded to ten digits each.
+	size := zeros + len(limbs)*base58LimbDigits
+	var outStack [base58StackDigits]byte
+	out := outStack[:]
+	if size > len(outStack) {
+		out = make([]byte, size)
+	}
+	i := size
+	if len(limbs) > 0 {
+		// Each full limb splits into 32-bit parts of 2, 4 and 4 digits,
+		// written as pairs.
+		for _, limb := range limbs[:len(limbs)-1] {
+			lo := uint32(limb % base58QuadRadix)
+			limb /= base58QuadRadix
+			mid := uint32(limb % base58QuadRadix)
+			hi := uint32(limb / base58QuadRadix)
+			i -= base58LimbDigits
+			o := out[i : i+base58LimbDigits]
+			a.putPair(o[0:2], hi)
+			a.putPair(o[2:4], mid/base58PairRadix)
+			a.putPair(o[4:6], mid%base58PairRadix)
+			a.putPair(o[6:8], lo/base58PairRadix)
+			a.putPair(o[8:10], lo%base58PairRadix)
+		}
+		for limb := limbs[len(limbs)-1]; limb != 0; limb /= base58PairRadix {
+			i -= 2
+			a.putPair(out[i:i+2], uint32(limb%base58PairRadix))
+		}
+		// The top pair may start with a zero digit, which is not part of
+		// the number.
+		if out[i] == a.chars[0] {
+			i++
+		}
+	}
+	for j := 0; j < zeros; j++ {
+		i--
+		out[i] = a.chars[0]
+	}
+	return string(out[i:size])
+}
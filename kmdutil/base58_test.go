@@ -0,0 +1,150 @@
+package kmdutil
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// Bitcoin Core's base58 vectors.  See:
+//
+//	https://github.com/bitcoin/bitcoin/blob/master/src/test/data/base58_encode_decode.json
+var base58Tests = []struct {
+	hex, encoded string
+}{
+	{"", ""},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+}
+
+func TestBase58Encode(t *testing.T) {
+	for _, tt := range base58Tests {
+		if got := Base58Encode(mustHex(t, tt.hex)); got != tt.encoded {
+			t.Errorf("Base58Encode(%s) = %q, want %q", tt.hex, got, tt.encoded)
+		}
+	}
+}
+
+func TestBase58Decode(t *testing.T) {
+	for _, tt := range base58Tests {
+		got, err := Base58Decode(tt.encoded)
+		if err != nil {
+			t.Errorf("Base58Decode(%q): %v", tt.encoded, err)
+			continue
+		}
+		if want := mustHex(t, tt.hex); !bytes.Equal(got, want) {
+			t.Errorf("Base58Decode(%q) = %x, want %x", tt.encoded, got, want)
+		}
+	}
+}
+
+func TestBase58DecodeInvalid(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+	}{
+		{"0", 0},
+		{"1O", 1},
+		{"3SEo3LWLoPntI", 12},
+		{"abl", 2},
+		{"a b", 1},
+	}
+	for _, tt := range tests {
+		_, err := Base58Decode(tt.in)
+		e, ok := err.(InvalidCharacterError)
+		if !ok {
+			t.Errorf("Base58Decode(%q) error = %v, want InvalidCharacterError", tt.in, err)
+			continue
+		}
+		if e.Pos != tt.pos || e.Char != tt.in[tt.pos] {
+			t.Errorf("Base58Decode(%q) error at %d %q, want %d %q", tt.in, e.Pos, e.Char, tt.pos, tt.in[tt.pos])
+		}
+	}
+}
+
+// base58EncodeBigInt is the straightforward big.Int encoder that the limb
+// based one replaced, kept as a reference.
+func base58EncodeBigInt(b []byte) string {
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	x := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// TestBase58Differential compares the encoder with base58EncodeBigInt and
+// checks the decoder round trip on random inputs of every length up to
+// 100 bytes, many of them with leading zero bytes.
+func TestBase58Differential(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		b := make([]byte, rng.Intn(101))
+		rng.Read(b)
+		if len(b) > 0 && rng.Intn(4) == 0 {
+			zeros := rng.Intn(len(b) + 1)
+			for j := 0; j < zeros; j++ {
+				b[j] = 0
+			}
+		}
+		got, want := Base58Encode(b), base58EncodeBigInt(b)
+		if got != want {
+			t.Fatalf("Base58Encode(%x) = %q, want %q", b, got, want)
+		}
+		dec, err := Base58Decode(got)
+		if err != nil || !bytes.Equal(dec, b) {
+			t.Fatalf("Base58Decode(%q) = %x, %v, want %x", got, dec, err, b)
+		}
+	}
+}
+
+// A 25-byte payload is the size of a version byte, a Hash160 and a
+// checksum, i.e. a P2PKH address.
+var benchPayload = []byte{
+	0x00, 0xeb, 0x15, 0x23, 0x1d, 0xfc, 0xeb, 0x60, 0x92, 0x58, 0x86, 0xb6, 0x7d,
+	0x06, 0x52, 0x99, 0x92, 0x59, 0x15, 0xae, 0xb1, 0x72, 0xc0, 0x66, 0x47,
+}
+
+func BenchmarkBase58Encode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Base58Encode(benchPayload)
+	}
+}
+
+func BenchmarkBase58EncodeBigInt(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		base58EncodeBigInt(benchPayload)
+	}
+}
+
+func BenchmarkBase58Decode(b *testing.B) {
+	s := Base58Encode(benchPayload)
+	for i := 0; i < b.N; i++ {
+		if _, err := Base58Decode(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+var (
+	// ErrChecksum indicates that the checksum of a Base58Check string
+	// does not match the data it covers.
+	ErrChecksum = errors.New("checksum error")
+
+	// ErrInvalidLength indicates that a Base58Check string is too short
+	// to hold the version prefix and the checksum.
+	ErrInvalidLength = errors.New("invalid format: version and/or checksum bytes missing")
+)
+
+// checksum returns the first four bytes of SHA256(SHA256(input)).  See:
+//
+//	Mastering Bitcoin, page 58
+func checksum(input []byte) []byte {
+	h := sha256.Sum256(input)
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
+
+// base58CheckDecode decodes s in Bitcoin's alphabet and verifies its
+// checksum.  It returns the data before the checksum, which holds at
+// least prefixLen bytes.
+func base58CheckDecode(s string, prefixLen int) ([]byte, error) {
+	return BitcoinAlphabet.checkDecode(s, prefixLen)
+}
+
+func (a *Base58Alphabet) checkDecode(s string, prefixLen int) ([]byte, error) {
+	decoded, err := a.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < prefixLen+4 {
+		return nil, ErrInvalidLength
+	}
+	data := decoded[:len(decoded)-4]
+	if !bytes.Equal(checksum(data), decoded[len(decoded)-4:]) {
+		return nil, ErrChecksum
+	}
+	return data, nil
+}
+
+// Base58CheckDecode decodes a Base58Check string such as an address or a
+// WIF and verifies its checksum.  It returns the version byte and the
+// payload between the version byte and the checksum.
+func Base58CheckDecode(s string) (version byte, payload []byte, err error) {
+	data, err := base58CheckDecode(s, 1)
+	if err != nil {
+		return 0, nil, err
+	}
+	return data[0], data[1:], nil
+}
+
+// Base58CheckDecodePrefix is Base58CheckDecode for a version prefix of
+// prefixLen bytes, e.g. 2 for Zcash t1... addresses.
+func Base58CheckDecodePrefix(s string, prefixLen int) (prefix, payload []byte, err error) {
+	return BitcoinAlphabet.CheckDecode(s, prefixLen)
+}
+
+// CheckDecode is Base58CheckDecodePrefix in alphabet a.
+func (a *Base58Alphabet) CheckDecode(s string, prefixLen int) (prefix, payload []byte, err error) {
+	if prefixLen < 1 {
+		return nil, nil, ErrInvalidLength
+	}
+	data, err := a.checkDecode(s, prefixLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data[:prefixLen], data[prefixLen:], nil
+}
+
+// Base58CheckEncode prepends the version byte to input, appends the
+// four-byte checksum and encodes the result in Base58.  See:
+//
+//	https://en.bitcoin.it/wiki/Technical_background_of_version_1_Bitcoin_addresses#How_to_create_Bitcoin_Address - Steps 5-7.
+func Base58CheckEncode(input []byte, version byte) string {
+	return Base58CheckEncodePrefix(input, []byte{version})
+}
+
+// Base58CheckEncodePrefix is Base58CheckEncode for a version prefix of
+// any length, such as the two-byte prefixes of Zcash and Horizen.
+func Base58CheckEncodePrefix(input, prefix []byte) string {
+	return BitcoinAlphabet.CheckEncode(input, prefix)
+}
+
+// CheckEncode is Base58CheckEncodePrefix in alphabet a.
+func (a *Base58Alphabet) CheckEncode(input, prefix []byte) string {
+	b := make([]byte, 0, len(prefix)+len(input)+4)
+	b = append(b, prefix...)
+	b = append(b, input...)
+	b = append(b, checksum(b)...)
+	return a.Encode(b)
+}
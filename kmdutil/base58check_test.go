@@ -0,0 +1,48 @@
+package kmdutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBase58CheckDecode(t *testing.T) {
+	tests := []struct {
+		in      string
+		version byte
+		payload string
+		err     error
+	}{
+		// The genesis block coinbase address.
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", 0x00, "62e907b15cbf27d5425399ebf6f0fb50ebb88f18", nil},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", 0x05, "b472a266d0bd89c13706a4132ccfb16f7c3b9fcb", nil},
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", 0x80,
+			"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", nil},
+		// Last character changed.
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", 0, "", ErrChecksum},
+		// Too short for a version byte and a checksum.
+		{"1111", 0, "", ErrInvalidLength},
+		{"", 0, "", ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		version, payload, err := Base58CheckDecode(tt.in)
+		if err != tt.err {
+			t.Errorf("Base58CheckDecode(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if version != tt.version || !bytes.Equal(payload, mustHex(t, tt.payload)) {
+			t.Errorf("Base58CheckDecode(%q) = %02x %x, want %02x %s", tt.in, version, payload, tt.version, tt.payload)
+		}
+		if got := Base58CheckEncode(payload, version); got != tt.in {
+			t.Errorf("Base58CheckEncode round trip = %q, want %q", got, tt.in)
+		}
+	}
+
+	if _, _, err := Base58CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7Div0Na"); err == nil {
+		t.Error("Base58CheckDecode accepted an invalid character")
+	} else if _, ok := err.(InvalidCharacterError); !ok {
+		t.Errorf("invalid character error = %T, want InvalidCharacterError", err)
+	}
+}
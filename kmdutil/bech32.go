@@ -0,0 +1,304 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bech32 and Bech32m encoding.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+//
+// The codec knows nothing about witness programs, so it serves SegWit
+// addresses as well as Lightning invoices, Nostr keys and any other
+// human-readable part (HRP).
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Index maps an input character to its value in bech32Charset, or
+// 0xff when the character is not part of the charset.
+var bech32Index [256]byte
+
+func init() {
+	for i := range bech32Index {
+		bech32Index[i] = 0xff
+	}
+	upper := strings.ToUpper(bech32Charset)
+	for i := 0; i < len(bech32Charset); i++ {
+		bech32Index[bech32Charset[i]] = byte(i)
+		bech32Index[upper[i]] = byte(i)
+	}
+}
+
+// Bech32Variant selects the checksum constant of the encoding.
+type Bech32Variant int
+
+const (
+	// Bech32 is the original BIP173 encoding, used by witness v0.
+	Bech32 Bech32Variant = iota + 1
+
+	// Bech32m is the BIP350 encoding, used by witness v1 and later.
+	Bech32m
+)
+
+func (v Bech32Variant) String() string {
+	switch v {
+	case Bech32:
+		return "bech32"
+	case Bech32m:
+		return "bech32m"
+	}
+	return fmt.Sprintf("Bech32Variant(%d)", int(v))
+}
+
+func (v Bech32Variant) constant() uint32 {
+	if v == Bech32m {
+		return 0x2bc830a3
+	}
+	return 1
+}
+
+// Bech32MaxLength is the BIP173 limit on the length of an encoded
+// string.  Bech32EncodeNoLimit and Bech32DecodeNoLimit lift it for
+// Lightning invoices.
+const Bech32MaxLength = 90
+
+var (
+	// ErrBech32MixedCase indicates a string with both upper and lower
+	// case characters.
+	ErrBech32MixedCase = errors.New("bech32: mixed case")
+
+	// ErrBech32Separator indicates a missing '1' separator, an empty
+	// HRP, or a data part shorter than the six checksum characters.
+	ErrBech32Separator = errors.New("bech32: invalid separator position")
+
+	// ErrBech32Checksum indicates that the checksum matches neither
+	// Bech32 nor Bech32m.
+	ErrBech32Checksum = errors.New("bech32: invalid checksum")
+
+	// ErrBech32Padding indicates non-zero or excess padding when
+	// converting between bit groups.
+	ErrBech32Padding = errors.New("bech32: invalid padding")
+)
+
+// Bech32LengthError is returned when a string or HRP is outside the
+// allowed length.
+type Bech32LengthError struct {
+	What     string
+	Length   int
+	Min, Max int
+}
+
+func (e Bech32LengthError) Error() string {
+	return fmt.Sprintf("bech32: invalid %s length %d, want %d..%d", e.What, e.Length, e.Min, e.Max)
+}
+
+// Bech32CharError is returned for a character that is not allowed at its
+// position.  Pos is the byte offset within the full string.
+type Bech32CharError struct {
+	Pos  int
+	Char byte
+}
+
+func (e Bech32CharError) Error() string {
+	return fmt.Sprintf("bech32: invalid character %q at position %d", e.Char, e.Pos)
+}
+
+// Bech32ValueError is returned for an input value too wide for its bit
+// group, e.g. a data value above 31 for Bech32Encode.  Pos is the index
+// of the value in the input.
+type Bech32ValueError struct {
+	Pos   int
+	Value byte
+	Bits  uint
+}
+
+func (e Bech32ValueError) Error() string {
+	return fmt.Sprintf("bech32: value %d at index %d does not fit in %d bits", e.Value, e.Pos, e.Bits)
+}
+
+// bech32Polymod computes the BCH checksum over the 5-bit values.
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand returns the HRP expanded for checksum computation.
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32Checksum(hrp string, data []byte, variant Bech32Variant) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := bech32Polymod(values) ^ variant.constant()
+	out := make([]byte, 6)
+	for i := range out {
+		out[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return out
+}
+
+// checkHRP validates the HRP characters and length.
+func checkHRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return Bech32LengthError{"hrp", len(hrp), 1, 83}
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return Bech32CharError{i, hrp[i]}
+		}
+	}
+	return nil
+}
+
+// Bech32Encode encodes the 5-bit groups in data under hrp with the given
+// checksum variant.  The result is always lower case and at most
+// Bech32MaxLength characters, so that Bech32Decode accepts it.
+func Bech32Encode(hrp string, data []byte, variant Bech32Variant) (string, error) {
+	if n := len(hrp) + 1 + len(data) + 6; n > Bech32MaxLength {
+		return "", Bech32LengthError{"string", n, 8, Bech32MaxLength}
+	}
+	return Bech32EncodeNoLimit(hrp, data, variant)
+}
+
+// Bech32EncodeNoLimit is Bech32Encode without the overall length limit,
+// as needed for BOLT11 Lightning invoices.
+func Bech32EncodeNoLimit(hrp string, data []byte, variant Bech32Variant) (string, error) {
+	if err := checkHRP(hrp); err != nil {
+		return "", err
+	}
+	if strings.ToLower(hrp) != hrp && strings.ToUpper(hrp) != hrp {
+		return "", ErrBech32MixedCase
+	}
+	hrp = strings.ToLower(hrp)
+	for i, v := range data {
+		if v > 31 {
+			return "", Bech32ValueError{i, v, 5}
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + 6)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for _, v := range bech32Checksum(hrp, data, variant) {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String(), nil
+}
+
+// Bech32Decode decodes a Bech32 or Bech32m string of at most
+// Bech32MaxLength characters.  It returns the lower case HRP, the 5-bit
+// data groups without the checksum, and the variant whose checksum
+// matched.
+func Bech32Decode(s string) (hrp string, data []byte, variant Bech32Variant, err error) {
+	if len(s) > Bech32MaxLength {
+		return "", nil, 0, Bech32LengthError{"string", len(s), 8, Bech32MaxLength}
+	}
+	return Bech32DecodeNoLimit(s)
+}
+
+// Bech32DecodeNoLimit is Bech32Decode without the overall length limit,
+// as needed for BOLT11 Lightning invoices.
+func Bech32DecodeNoLimit(s string) (hrp string, data []byte, variant Bech32Variant, err error) {
+	if len(s) < 8 {
+		return "", nil, 0, Bech32LengthError{"string", len(s), 8, Bech32MaxLength}
+	}
+	lower, upper := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			return "", nil, 0, Bech32CharError{i, c}
+		}
+		lower = lower || (c >= 'a' && c <= 'z')
+		upper = upper || (c >= 'A' && c <= 'Z')
+	}
+	if lower && upper {
+		return "", nil, 0, ErrBech32MixedCase
+	}
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, ErrBech32Separator
+	}
+	hrp = strings.ToLower(s[:sep])
+	if err := checkHRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+
+	values := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		v := bech32Index[s[i]]
+		if v == 0xff {
+			return "", nil, 0, Bech32CharError{i, s[i]}
+		}
+		values = append(values, v)
+	}
+
+	switch bech32Polymod(append(bech32HRPExpand(hrp), values...)) {
+	case Bech32.constant():
+		variant = Bech32
+	case Bech32m.constant():
+		variant = Bech32m
+	default:
+		return "", nil, 0, ErrBech32Checksum
+	}
+	return hrp, values[:len(values)-6], variant, nil
+}
+
+// ConvertBits regroups data from fromBits-wide to toBits-wide groups,
+// e.g. 8 to 5 before Bech32Encode and 5 to 8 after Bech32Decode.  When
+// pad is true an incomplete final group is zero padded; otherwise any
+// leftover bits must be zero padding shorter than fromBits.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	if fromBits < 1 || fromBits > 8 || toBits < 1 || toBits > 8 {
+		return nil, errors.New("bech32: bit groups must be 1 to 8 bits wide")
+	}
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for i, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, Bech32ValueError{i, v, fromBits}
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, ErrBech32Padding
+	}
+	return out, nil
+}
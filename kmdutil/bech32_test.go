@@ -0,0 +1,116 @@
+package kmdutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// BIP173 and BIP350 checksum vectors.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#test-vectors
+//	https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#test-vectors
+func TestBech32Valid(t *testing.T) {
+	tests := []struct {
+		s       string
+		variant Bech32Variant
+	}{
+		{"A12UEL5L", Bech32},
+		{"a12uel5l", Bech32},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", Bech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", Bech32},
+		{"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j", Bech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", Bech32},
+		{"?1ezyfcl", Bech32},
+		{"A1LQFN3A", Bech32m},
+		{"a1lqfn3a", Bech32m},
+		{"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", Bech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", Bech32m},
+		{"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8", Bech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", Bech32m},
+		{"?1v759aa", Bech32m},
+	}
+	for _, tt := range tests {
+		hrp, data, variant, err := Bech32Decode(tt.s)
+		if err != nil {
+			t.Errorf("Bech32Decode(%q): %v", tt.s, err)
+			continue
+		}
+		if variant != tt.variant {
+			t.Errorf("Bech32Decode(%q) variant = %v, want %v", tt.s, variant, tt.variant)
+		}
+		enc, err := Bech32Encode(hrp, data, variant)
+		if err != nil || enc != strings.ToLower(tt.s) {
+			t.Errorf("Bech32Encode round trip of %q = %q, %v", tt.s, enc, err)
+		}
+	}
+}
+
+func TestBech32Invalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"\x201nwldj5", Bech32CharError{0, 0x20}},
+		{"\x7f1axkwrx", Bech32CharError{0, 0x7f}},
+		{"\x801eym55h", Bech32CharError{0, 0x80}},
+		{"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+			Bech32LengthError{"string", 91, 8, Bech32MaxLength}},
+		{"pzry9x0s0muk", ErrBech32Separator},
+		{"1pzry9x0s0muk", ErrBech32Separator},
+		{"x1b4n0q5v", Bech32CharError{2, 'b'}},
+		{"li1dgmt3", ErrBech32Separator},
+		{"de1lg7wt\xff", Bech32CharError{8, 0xff}},
+		{"A1G7SGD8", ErrBech32Checksum},
+		{"1qzzfhee", ErrBech32Separator},
+		{"A12uEL5L", ErrBech32MixedCase},
+	}
+	for _, tt := range tests {
+		_, _, _, err := Bech32Decode(tt.s)
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("Bech32Decode(%q) error = %v, want %v", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestBech32DecodeNoLimit(t *testing.T) {
+	data := make([]byte, 100)
+	want := Bech32LengthError{"string", 111, 8, Bech32MaxLength}
+	if _, err := Bech32Encode("lnbc", data, Bech32); err != want {
+		t.Errorf("Bech32Encode(111 characters) error = %v, want %v", err, want)
+	}
+	s, err := Bech32EncodeNoLimit("lnbc", data, Bech32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := Bech32Decode(s); err == nil {
+		t.Errorf("Bech32Decode accepted a %d-character string", len(s))
+	}
+	if _, got, _, err := Bech32DecodeNoLimit(s); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Bech32DecodeNoLimit = %v, %v", got, err)
+	}
+}
+
+func TestConvertBits(t *testing.T) {
+	in := mustHex(t, "751e76e8199196d454941c45d1b3a323f1433bd6")
+	five, err := ConvertBits(in, 8, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := ConvertBits(five, 5, 8, false)
+	if err != nil || !bytes.Equal(back, in) {
+		t.Errorf("ConvertBits round trip = %x, %v, want %x", back, err, in)
+	}
+	// 0x1f leaves four non-zero bits of padding going from 5 to 8.
+	if _, err := ConvertBits([]byte{0x1f}, 5, 8, false); err != ErrBech32Padding {
+		t.Errorf("ConvertBits non-zero padding error = %v, want %v", err, ErrBech32Padding)
+	}
+	want := Bech32ValueError{1, 0x20, 5}
+	if _, err := ConvertBits([]byte{0x1f, 0x20}, 5, 8, true); err != want {
+		t.Errorf("ConvertBits(wide value) error = %v, want %v", err, want)
+	}
+	if _, err := Bech32Encode("bc", []byte{0, 32}, Bech32); err != (Bech32ValueError{1, 32, 5}) {
+		t.Errorf("Bech32Encode(wide value) error = %v, want %v", err, Bech32ValueError{1, 32, 5})
+	}
+}
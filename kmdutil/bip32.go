@@ -0,0 +1,350 @@
+package kmdutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BIP32 hierarchical deterministic keys.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+//
+//	I = HMAC-SHA512(key = "Bitcoin seed", data = seed)
+//	master key = I[:32], master chain code = I[32:]
+//
+// A child is derived from HMAC-SHA512(chain code, data || index), where
+// data is the 33-byte compressed parent public key, or 0x00 || parent
+// private key for hardened indexes (index >= 2^31).  Extended keys are
+// serialized as Base58Check of
+//
+//	version(4) || depth(1) || parent fingerprint(4) || child number(4) ||
+//	chain code(32) || key(33)
+
+// HardenedKeyStart is the first hardened child index, written 0' or 0h in
+// derivation paths.
+const HardenedKeyStart = 0x80000000
+
+const extendedKeyLen = 78
+
+var bip32SeedKey = []byte("Bitcoin seed")
+
+var (
+	// ErrSeedLength indicates a seed shorter than 128 or longer than 512
+	// bits.
+	ErrSeedLength = errors.New("seed must be 16 to 64 bytes")
+
+	// ErrInvalidChild is returned for the about 1 in 2^127 indexes whose
+	// key is invalid.  BIP32 says to proceed with the next index.
+	ErrInvalidChild = errors.New("derived key is invalid")
+
+	// ErrHardenedPublic indicates a hardened derivation from a public
+	// extended key.
+	ErrHardenedPublic = errors.New("cannot derive a hardened child from a public key")
+
+	// ErrPublicExtendedKey is returned when asking a public extended key
+	// for its private key.
+	ErrPublicExtendedKey = errors.New("public extended key has no private key")
+
+	// ErrDeriveDepth is returned when deriving beyond depth 255.
+	ErrDeriveDepth = errors.New("cannot derive beyond depth 255")
+
+	// ErrExtendedKeyFormat indicates a string that is not a 78-byte
+	// extended key with a valid key part.
+	ErrExtendedKeyFormat = errors.New("invalid extended key format")
+
+	// ErrUnknownHDVersion is returned when no registered network has the
+	// version bytes of an extended key.
+	ErrUnknownHDVersion = errors.New("unknown extended key version")
+
+	// ErrDerivationPath indicates a malformed path such as "m/44'/x".
+	ErrDerivationPath = errors.New("invalid derivation path")
+)
+
+// ExtendedKey is a BIP32 private or public key together with its chain
+// code and position in the tree.
+type ExtendedKey struct {
+	// Net gives the version bytes used by String.
+	Net *Network
+
+	// Script is the script type of the addresses below the key.  Other
+	// than ScriptP2PKH it selects a SLIP-132 version such as zpub, where
+	// SLIP-132 defines one.
+	Script ScriptType
+
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+	ChainCode         [32]byte
+
+	// key is the 32-byte private key, or nil for a public extended key.
+	key []byte
+	// pubKey is the 33-byte compressed public key, computed lazily for
+	// private keys.
+	pubKey []byte
+}
+
+// NewMasterKey returns the master private key of a 16 to 64-byte seed.
+func NewMasterKey(seed []byte, net *Network) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrSeedLength
+	}
+	mac := hmac.New(sha512.New, bip32SeedKey)
+	mac.Write(seed)
+	I := mac.Sum(nil)
+	if !validPrivKey(new(big.Int).SetBytes(I[:32])) {
+		return nil, ErrInvalidChild
+	}
+	k := &ExtendedKey{Net: net, key: I[:32]}
+	copy(k.ChainCode[:], I[32:])
+	return k, nil
+}
+
+// IsPrivate reports whether k holds a private key.
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.key != nil
+}
+
+// PubKey returns the 33-byte compressed public key.
+func (k *ExtendedKey) PubKey() []byte {
+	if k.pubKey == nil {
+		k.pubKey = ECBaseMul(new(big.Int).SetBytes(k.key)).Serialize()
+	}
+	return k.pubKey
+}
+
+// PublicKey returns the public key point.
+func (k *ExtendedKey) PublicKey() Point {
+	P, _ := ParsePubKey(k.PubKey())
+	return P
+}
+
+// PrivateKey returns the private key, or an error for a public extended
+// key.
+func (k *ExtendedKey) PrivateKey() (PrivateKey, error) {
+	if !k.IsPrivate() {
+		return PrivateKey{}, ErrPublicExtendedKey
+	}
+	return PrivateKeyFromBytes(k.key)
+}
+
+// Fingerprint returns the first 4 bytes of Hash160 of the public key,
+// which children record as their parent fingerprint.
+func (k *ExtendedKey) Fingerprint() [4]byte {
+	var fp [4]byte
+	copy(fp[:], Hash160(k.PubKey()))
+	return fp
+}
+
+// Neuter returns the public extended key of k.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	return &ExtendedKey{
+		Net:               k.Net,
+		Script:            k.Script,
+		Depth:             k.Depth,
+		ParentFingerprint: k.ParentFingerprint,
+		ChildNumber:       k.ChildNumber,
+		ChainCode:         k.ChainCode,
+		pubKey:            k.PubKey(),
+	}
+}
+
+// Child derives child i of k: CKDpriv for a private key and CKDpub for a
+// public key.  Indexes from HardenedKeyStart on are hardened and need a
+// private key.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	if k.Depth == 255 {
+		return nil, ErrDeriveDepth
+	}
+	data := make([]byte, 37)
+	if i >= HardenedKeyStart {
+		if !k.IsPrivate() {
+			return nil, ErrHardenedPublic
+		}
+		copy(data[1:], k.key)
+	} else {
+		copy(data, k.PubKey())
+	}
+	binary.BigEndian.PutUint32(data[33:], i)
+
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	mac.Write(data)
+	I := mac.Sum(nil)
+	IL := new(big.Int).SetBytes(I[:32])
+	if IL.Cmp(curveN) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	child := &ExtendedKey{
+		Net:               k.Net,
+		Script:            k.Script,
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		ChildNumber:       i,
+	}
+	copy(child.ChainCode[:], I[32:])
+	if k.IsPrivate() {
+		// k_i = IL + k_par   mod n
+		d := IL.Add(IL, new(big.Int).SetBytes(k.key))
+		d.Mod(d, curveN)
+		if d.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		child.key = make([]byte, 32)
+		d.FillBytes(child.key)
+	} else {
+		// K_i = IL*G + K_par
+		K := NewPoint().ECPointAdd(ECBaseMul(IL), k.PublicKey())
+		if K.IsInfinity() {
+			return nil, ErrInvalidChild
+		}
+		child.pubKey = K.Serialize()
+	}
+	return child, nil
+}
+
+// Derive follows a derivation path such as "m/44'/141'/0'/0/5" from k,
+// which is taken to be the key at "m".
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range indexes {
+		if k, err = k.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// ParseDerivationPath parses a path of the form m/a/b'/c, where a
+// hardened index is marked with ', h or H.  "m" alone is the empty path.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: %q must start with m", ErrDerivationPath, path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		var offset uint32
+		if n := len(p); n > 0 && (p[n-1] == '\'' || p[n-1] == 'h' || p[n-1] == 'H') {
+			offset = HardenedKeyStart
+			p = p[:n-1]
+		}
+		// Only plain decimal digits, so that "+1" or " 1" are rejected.
+		if p == "" || strings.Trim(p, "0123456789") != "" {
+			return nil, fmt.Errorf("%w: bad index %q in %q", ErrDerivationPath, p, path)
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || i >= HardenedKeyStart {
+			return nil, fmt.Errorf("%w: index %s out of range in %q", ErrDerivationPath, p, path)
+		}
+		indexes = append(indexes, uint32(i)+offset)
+	}
+	return indexes, nil
+}
+
+// FormatDerivationPath is the inverse of ParseDerivationPath, writing
+// hardened indexes with an apostrophe.
+func FormatDerivationPath(indexes []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, i := range indexes {
+		if i >= HardenedKeyStart {
+			fmt.Fprintf(&sb, "/%d'", i-HardenedKeyStart)
+		} else {
+			fmt.Fprintf(&sb, "/%d", i)
+		}
+	}
+	return sb.String()
+}
+
+// String returns the Base58Check serialization, e.g. xprv... or xpub...
+func (k *ExtendedKey) String() string {
+	b := make([]byte, 0, extendedKeyLen)
+	v := k.version()
+	b = append(b, v[:]...)
+	b = append(b, k.Depth)
+	b = append(b, k.ParentFingerprint[:]...)
+	var child [4]byte
+	binary.BigEndian.PutUint32(child[:], k.ChildNumber)
+	b = append(b, child[:]...)
+	b = append(b, k.ChainCode[:]...)
+	if k.IsPrivate() {
+		b = append(b, 0x00)
+		b = append(b, k.key...)
+	} else {
+		b = append(b, k.pubKey...)
+	}
+	return Base58CheckEncodePrefix(b[4:], b[:4])
+}
+
+// ParseExtendedKey decodes a serialized extended key, including the
+// SLIP-132 versions such as ypub and zpub.  Its network is the first
+// registered one with matching version bytes, so an xprv resolves to
+// Bitcoin even though Komodo and others share the version.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	b, err := base58CheckDecode(s, 4)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != extendedKeyLen {
+		return nil, ErrExtendedKeyFormat
+	}
+	var v [4]byte
+	copy(v[:], b)
+	var net *Network
+	var script ScriptType
+	var private bool
+	if row, priv, ok := slip132Lookup(v); ok {
+		// A SLIP-132 version stands for the plain one plus a script type.
+		for _, n := range Networks() {
+			if n.HDPublicKeyID == row.base {
+				net, script, private = n, row.script, priv
+				break
+			}
+		}
+	} else {
+		for _, n := range Networks() {
+			if n.HDPrivateKeyID == v || n.HDPublicKeyID == v {
+				net, private = n, n.HDPrivateKeyID == v
+				break
+			}
+		}
+	}
+	if net == nil {
+		return nil, fmt.Errorf("%w: %x", ErrUnknownHDVersion, v)
+	}
+
+	k := &ExtendedKey{
+		Net:         net,
+		Script:      script,
+		Depth:       b[4],
+		ChildNumber: binary.BigEndian.Uint32(b[9:13]),
+	}
+	copy(k.ParentFingerprint[:], b[5:9])
+	copy(k.ChainCode[:], b[13:45])
+	keyData := b[45:]
+	if private {
+		if keyData[0] != 0x00 || !validPrivKey(new(big.Int).SetBytes(keyData[1:])) {
+			return nil, ErrExtendedKeyFormat
+		}
+		k.key = keyData[1:]
+	} else {
+		if _, err := ParsePubKey(keyData); err != nil {
+			return nil, ErrExtendedKeyFormat
+		}
+		k.pubKey = keyData
+	}
+	// A master key has neither a parent nor a child number.
+	if k.Depth == 0 && (k.ParentFingerprint != [4]byte{} || k.ChildNumber != 0) {
+		return nil, ErrExtendedKeyFormat
+	}
+	return k, nil
+}
@@ -0,0 +1,186 @@
+package kmdutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// bip32Vector is one step of a BIP32 test vector chain.
+type bip32Vector struct {
+	path string
+	xpub string
+	xprv string
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	tests := []struct {
+		name  string
+		seed  string
+		chain []bip32Vector
+	}{
+		{
+			"vector 1",
+			"000102030405060708090a0b0c0d0e0f",
+			[]bip32Vector{
+				{"m",
+					"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+					"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+				{"m/0H",
+					"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+					"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+				{"m/0H/1",
+					"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+					"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs"},
+				{"m/0H/1/2H",
+					"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+					"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM"},
+				{"m/0H/1/2H/2",
+					"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+					"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334"},
+				{"m/0H/1/2H/2/1000000000",
+					"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+					"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76"},
+			},
+		},
+		{
+			// Retention of leading zeros in the private key.
+			"vector 3",
+			"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4ac" +
+				"ba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+			[]bip32Vector{
+				{"m",
+					"xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+					"xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6"},
+				{"m/0H",
+					"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+					"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L"},
+			},
+		},
+		{
+			// Retention of leading zeros in the public key x coordinate.
+			"vector 4",
+			"3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678",
+			[]bip32Vector{
+				{"m",
+					"xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+					"xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv"},
+				{"m/0H",
+					"xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+					"xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G"},
+				{"m/0H/1H",
+					"xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+					"xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		master, err := NewMasterKey(mustHex(t, tt.seed), BTCMainnet)
+		if err != nil {
+			t.Fatalf("%s: NewMasterKey: %v", tt.name, err)
+		}
+		for _, v := range tt.chain {
+			k, err := master.Derive(v.path)
+			if err != nil {
+				t.Fatalf("%s: Derive(%s): %v", tt.name, v.path, err)
+			}
+			if got := k.String(); got != v.xprv {
+				t.Errorf("%s %s: xprv = %s, want %s", tt.name, v.path, got, v.xprv)
+			}
+			if got := k.Neuter().String(); got != v.xpub {
+				t.Errorf("%s %s: xpub = %s, want %s", tt.name, v.path, got, v.xpub)
+			}
+
+			// Both serializations parse back to the same key.
+			for _, s := range []string{v.xprv, v.xpub} {
+				p, err := ParseExtendedKey(s)
+				if err != nil {
+					t.Errorf("ParseExtendedKey(%s): %v", s, err)
+					continue
+				}
+				if p.String() != s {
+					t.Errorf("ParseExtendedKey(%s).String() = %s", s, p.String())
+				}
+			}
+		}
+	}
+}
+
+func TestBIP32PublicDerivation(t *testing.T) {
+	// CKDpub of the neutered parent matches CKDpriv for normal indexes.
+	master, _ := NewMasterKey(mustHex(t, "000102030405060708090a0b0c0d0e0f"), BTCMainnet)
+	parent, _ := master.Derive("m/0H/1")
+	priv, _ := parent.Derive("m/2/1000000000")
+	pub, err := parent.Neuter().Derive("m/2/1000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.String() != priv.Neuter().String() {
+		t.Errorf("CKDpub = %s, want %s", pub, priv.Neuter())
+	}
+	if _, err := parent.Neuter().Child(HardenedKeyStart); err != ErrHardenedPublic {
+		t.Errorf("hardened child of a public key error = %v, want %v", err, ErrHardenedPublic)
+	}
+	if _, err := pub.PrivateKey(); err != ErrPublicExtendedKey {
+		t.Errorf("PrivateKey of a public extended key error = %v, want %v", err, ErrPublicExtendedKey)
+	}
+}
+
+func TestParseExtendedKeyInvalid(t *testing.T) {
+	// From BIP32 test vector 5.
+	tests := []struct {
+		s    string
+		err  error
+		desc string
+	}{
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm",
+			ErrExtendedKeyFormat, "pubkey version / prvkey mismatch"},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH",
+			ErrExtendedKeyFormat, "prvkey version / pubkey mismatch"},
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn",
+			ErrExtendedKeyFormat, "invalid pubkey prefix 04"},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx",
+			ErrExtendedKeyFormat, "private key 0 not in 1..n-1"},
+		{"xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+			ErrExtendedKeyFormat, "zero depth with non-zero parent fingerprint"},
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBj",
+			ErrChecksum, "invalid checksum"},
+		{"DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4",
+			ErrUnknownHDVersion, "unknown extended key version"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseExtendedKey(tt.s); !errors.Is(err, tt.err) {
+			t.Errorf("ParseExtendedKey (%s) error = %v, want %v", tt.desc, err, tt.err)
+		}
+	}
+}
+
+func TestDerivationPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		indexes []uint32
+		format  string
+	}{
+		{"m", []uint32{}, "m"},
+		{"m/44'/141'/0'/0/5", []uint32{HardenedKeyStart + 44, HardenedKeyStart + 141, HardenedKeyStart, 0, 5}, "m/44'/141'/0'/0/5"},
+		{"m/0h/1H/2", []uint32{HardenedKeyStart, HardenedKeyStart + 1, 2}, "m/0'/1'/2"},
+		{"m/2147483647'", []uint32{0xffffffff}, "m/2147483647'"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDerivationPath(tt.path)
+		if err != nil || !reflect.DeepEqual(got, tt.indexes) {
+			t.Errorf("ParseDerivationPath(%q) = %v, %v, want %v", tt.path, got, err, tt.indexes)
+		}
+		if s := FormatDerivationPath(tt.indexes); s != tt.format {
+			t.Errorf("FormatDerivationPath(%v) = %q, want %q", tt.indexes, s, tt.format)
+		}
+	}
+	for _, path := range []string{"", "44'/0'", "m/", "m/x", "m/+1", "m/1''", "m/2147483648"} {
+		if _, err := ParseDerivationPath(path); !errors.Is(err, ErrDerivationPath) {
+			t.Errorf("ParseDerivationPath(%q) error = %v, want %v", path, err, ErrDerivationPath)
+		}
+	}
+	if _, err := NewMasterKey(make([]byte, 15), BTCMainnet); err != ErrSeedLength {
+		t.Errorf("NewMasterKey(15 bytes) error = %v, want %v", err, ErrSeedLength)
+	}
+}
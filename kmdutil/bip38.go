@@ -0,0 +1,365 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// BIP38 passphrase-protected private keys.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0038.mediawiki
+//
+// Passphrases are NFC normalized before use, as BIP38 requires, so the
+// composed and decomposed forms of a non-ASCII passphrase give the same
+// key.
+
+const (
+	bip38NonEC      = 0x42
+	bip38EC         = 0x43
+	bip38FlagNonEC  = 0xc0
+	bip38FlagComp   = 0x20
+	bip38FlagLotSeq = 0x04
+)
+
+var (
+	bip38IntermediateMagic = []byte{0x2c, 0xe9, 0xb3, 0xe1, 0xff, 0x39, 0xe2} // + 0x51, or 0x53 with lot/sequence
+	bip38ConfirmMagic      = []byte{0x64, 0x3b, 0xf6, 0xa8, 0x9a}
+)
+
+var (
+	// ErrBIP38Format indicates a string that is not a BIP38 key,
+	// intermediate code or confirmation code.
+	ErrBIP38Format = errors.New("invalid BIP38 format")
+
+	// ErrBIP38Passphrase indicates that the decrypted key does not match
+	// the address hash, which almost always means a wrong passphrase.
+	ErrBIP38Passphrase = errors.New("wrong BIP38 passphrase")
+
+	// ErrBIP38LotSequence indicates a lot above 1048575 or a sequence
+	// above 4095.
+	ErrBIP38LotSequence = errors.New("BIP38 lot must be 0..1048575 and sequence 0..4095")
+)
+
+// BIP38LotSequence is the optional lot and sequence number embedded in an
+// EC-multiply intermediate code.
+type BIP38LotSequence struct {
+	Lot, Sequence uint32
+}
+
+// sha256d is SHA256(SHA256(b)).
+func sha256d(b []byte) []byte {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	return h[:]
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// aesEncrypt and aesDecrypt process a single 16-byte block with AES-256
+// under key, as BIP38 does (ECB mode without padding).
+func aesEncrypt(block, key []byte) []byte {
+	c, _ := aes.NewCipher(key)
+	out := make([]byte, 16)
+	c.Encrypt(out, block)
+	return out
+}
+
+func aesDecrypt(block, key []byte) []byte {
+	c, _ := aes.NewCipher(key)
+	out := make([]byte, 16)
+	c.Decrypt(out, block)
+	return out
+}
+
+// bip38AddressHash returns the first four bytes of SHA256(SHA256(address))
+// of the P2PKH address of P.
+func bip38AddressHash(P Point, compressed bool, net *Network) []byte {
+	pubKey := P.SerializeUncompressed()
+	if compressed {
+		pubKey = P.Serialize()
+	}
+	return checksum([]byte(P2PKHAddress(pubKey, net)))
+}
+
+// BIP38Encrypt encrypts k with passphrase in the non-EC-multiply mode.
+// The address hash check uses the P2PKH address of net, so decrypting
+// needs the same network.
+func BIP38Encrypt(k PrivateKey, passphrase string, net *Network, compressed bool) (string, error) {
+	flag := byte(bip38FlagNonEC)
+	if compressed {
+		flag |= bip38FlagComp
+	}
+	addrHash := bip38AddressHash(k.PublicKey(), compressed, net)
+	derived, err := scrypt.Key(bip38Passphrase(passphrase), addrHash, 16384, 8, 8, 64)
+	if err != nil {
+		return "", err
+	}
+	half1, half2 := derived[:32], derived[32:]
+
+	b := []byte{0x01, bip38NonEC, flag}
+	b = append(b, addrHash...)
+	b = append(b, aesEncrypt(xorBytes(k[:16], half1[:16]), half2)...)
+	b = append(b, aesEncrypt(xorBytes(k[16:], half1[16:]), half2)...)
+	return Base58CheckEncode(b[1:], b[0]), nil
+}
+
+// BIP38Decrypt decrypts a 6P... key made in either mode and reports
+// whether it belongs to a compressed public key.
+func BIP38Decrypt(encrypted, passphrase string, net *Network) (k PrivateKey, compressed bool, err error) {
+	version, payload, err := Base58CheckDecode(encrypted)
+	if err != nil {
+		return k, false, err
+	}
+	if version != 0x01 || len(payload) != 38 {
+		return k, false, ErrBIP38Format
+	}
+	mode, flag, addrHash := payload[0], payload[1], payload[2:6]
+	compressed = flag&bip38FlagComp != 0
+
+	var d *big.Int
+	switch mode {
+	case bip38NonEC:
+		if flag&^bip38FlagComp != bip38FlagNonEC {
+			return k, false, ErrBIP38Format
+		}
+		derived, err := scrypt.Key(bip38Passphrase(passphrase), addrHash, 16384, 8, 8, 64)
+		if err != nil {
+			return k, false, err
+		}
+		half1, half2 := derived[:32], derived[32:]
+		key := append(
+			xorBytes(aesDecrypt(payload[6:22], half2), half1[:16]),
+			xorBytes(aesDecrypt(payload[22:38], half2), half1[16:])...)
+		d = new(big.Int).SetBytes(key)
+
+	case bip38EC:
+		if flag&^(bip38FlagComp|bip38FlagLotSeq) != 0 {
+			return k, false, ErrBIP38Format
+		}
+		ownerEntropy := payload[6:14]
+		passFactor, err := bip38PassFactor(passphrase, ownerEntropy, flag&bip38FlagLotSeq != 0)
+		if err != nil {
+			return k, false, err
+		}
+		passPoint := ECBaseMul(passFactor).Serialize()
+		half1, half2, err := bip38ECDerive(passPoint, addrHash, ownerEntropy)
+		if err != nil {
+			return k, false, err
+		}
+		// encryptedpart2 decrypts to encryptedpart1[8:16] || seedb[16:24].
+		part2 := xorBytes(aesDecrypt(payload[22:38], half2), half1[16:])
+		part1 := append(append([]byte{}, payload[14:22]...), part2[:8]...)
+		seedb := append(xorBytes(aesDecrypt(part1, half2), half1[:16]), part2[8:]...)
+		d = new(big.Int).Mul(passFactor, new(big.Int).SetBytes(sha256d(seedb)))
+		d.Mod(d, curveN)
+
+	default:
+		return k, false, ErrBIP38Format
+	}
+
+	k, err = NewPrivateKey(d)
+	if err != nil {
+		return k, false, ErrBIP38Passphrase
+	}
+	if !bytes.Equal(bip38AddressHash(k.PublicKey(), compressed, net), addrHash) {
+		return PrivateKey{}, false, ErrBIP38Passphrase
+	}
+	return k, compressed, nil
+}
+
+// bip38Passphrase returns the NFC normalized UTF-8 bytes of passphrase.
+func bip38Passphrase(passphrase string) []byte {
+	return []byte(norm.NFC.String(passphrase))
+}
+
+// bip38PassFactor derives passfactor from the passphrase and the 8-byte
+// owner entropy, which is ownersalt(4) || lotsequence(4) when lotSeq is
+// set and ownersalt(8) otherwise.
+func bip38PassFactor(passphrase string, ownerEntropy []byte, lotSeq bool) (*big.Int, error) {
+	salt := ownerEntropy
+	if lotSeq {
+		salt = ownerEntropy[:4]
+	}
+	prefactor, err := scrypt.Key(bip38Passphrase(passphrase), salt, 16384, 8, 8, 32)
+	if err != nil {
+		return nil, err
+	}
+	if lotSeq {
+		prefactor = sha256d(append(prefactor, ownerEntropy...))
+	}
+	passFactor := new(big.Int).SetBytes(prefactor)
+	if !validPrivKey(passFactor) {
+		return nil, errInvalidPrivKey
+	}
+	return passFactor, nil
+}
+
+// bip38ECDerive returns the two halves of scrypt(passpoint,
+// addresshash || ownerentropy, 1024, 1, 1, 64).
+func bip38ECDerive(passPoint, addrHash, ownerEntropy []byte) (half1, half2 []byte, err error) {
+	salt := append(append([]byte{}, addrHash...), ownerEntropy...)
+	derived, err := scrypt.Key(passPoint, salt, 1024, 1, 1, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return derived[:32], derived[32:], nil
+}
+
+// BIP38Intermediate returns a passphrase... intermediate code for
+// passphrase with a random owner salt.  The code lets a third party make
+// encrypted keys without learning the passphrase.  lotSeq may be nil.
+func BIP38Intermediate(passphrase string, lotSeq *BIP38LotSequence) (string, error) {
+	ownerEntropy := make([]byte, 8)
+	saltLen := 8
+	if lotSeq != nil {
+		if lotSeq.Lot > 1048575 || lotSeq.Sequence > 4095 {
+			return "", ErrBIP38LotSequence
+		}
+		binary.BigEndian.PutUint32(ownerEntropy[4:], lotSeq.Lot*4096+lotSeq.Sequence)
+		saltLen = 4
+	}
+	if _, err := rand.Read(ownerEntropy[:saltLen]); err != nil {
+		return "", err
+	}
+	passFactor, err := bip38PassFactor(passphrase, ownerEntropy, lotSeq != nil)
+	if err != nil {
+		return "", err
+	}
+
+	b := append([]byte{}, bip38IntermediateMagic...)
+	if lotSeq != nil {
+		b = append(b, 0x53)
+	} else {
+		b = append(b, 0x51)
+	}
+	b = append(b, ownerEntropy...)
+	b = append(b, ECBaseMul(passFactor).Serialize()...)
+	return Base58CheckEncodePrefix(b[1:], b[:1]), nil
+}
+
+// BIP38EncryptFromIntermediate makes a new random key from an
+// intermediate code.  It returns the 6P... encrypted key, the cfrm38...
+// confirmation code and the key's P2PKH address on net.
+func BIP38EncryptFromIntermediate(intermediate string, compressed bool, net *Network) (encrypted, confirmation, address string, err error) {
+	data, err := base58CheckDecode(intermediate, 8)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(data) != 49 || !bytes.Equal(data[:7], bip38IntermediateMagic) ||
+		data[7] != 0x51 && data[7] != 0x53 {
+		return "", "", "", ErrBIP38Format
+	}
+	ownerEntropy, passPointBytes := data[8:16], data[16:49]
+	passPoint, err := ParsePubKey(passPointBytes)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var flag byte
+	if compressed {
+		flag |= bip38FlagComp
+	}
+	if data[7] == 0x53 {
+		flag |= bip38FlagLotSeq
+	}
+
+	seedb := make([]byte, 24)
+	var factorB *big.Int
+	for {
+		if _, err := rand.Read(seedb); err != nil {
+			return "", "", "", err
+		}
+		factorB = new(big.Int).SetBytes(sha256d(seedb))
+		if validPrivKey(factorB) {
+			break
+		}
+	}
+	generated := NewPoint().ECPointMul(factorB, passPoint)
+	pubKey := generated.SerializeUncompressed()
+	if compressed {
+		pubKey = generated.Serialize()
+	}
+	address = P2PKHAddress(pubKey, net)
+	addrHash := checksum([]byte(address))
+
+	half1, half2, err := bip38ECDerive(passPointBytes, addrHash, ownerEntropy)
+	if err != nil {
+		return "", "", "", err
+	}
+	part1 := aesEncrypt(xorBytes(seedb[:16], half1[:16]), half2)
+	part2 := aesEncrypt(xorBytes(append(append([]byte{}, part1[8:]...), seedb[16:]...), half1[16:]), half2)
+
+	b := []byte{0x01, bip38EC, flag}
+	b = append(b, addrHash...)
+	b = append(b, ownerEntropy...)
+	b = append(b, part1[:8]...)
+	b = append(b, part2...)
+	encrypted = Base58CheckEncode(b[1:], b[0])
+
+	// The confirmation code carries factorb*G encrypted the same way, so
+	// the owner can check the address before funding it.
+	pointB := ECBaseMul(factorB).Serialize()
+	c := append([]byte{}, bip38ConfirmMagic...)
+	c = append(c, flag)
+	c = append(c, addrHash...)
+	c = append(c, ownerEntropy...)
+	c = append(c, pointB[0]^(half2[31]&0x01))
+	c = append(c, aesEncrypt(xorBytes(pointB[1:17], half1[:16]), half2)...)
+	c = append(c, aesEncrypt(xorBytes(pointB[17:33], half1[16:]), half2)...)
+	confirmation = Base58CheckEncodePrefix(c[1:], c[:1])
+	return encrypted, confirmation, address, nil
+}
+
+// BIP38VerifyConfirmation checks a confirmation code against passphrase
+// and returns the P2PKH address on net that the encrypted key belongs to.
+func BIP38VerifyConfirmation(confirmation, passphrase string, net *Network) (string, error) {
+	data, err := base58CheckDecode(confirmation, 5)
+	if err != nil {
+		return "", err
+	}
+	if len(data) != 51 || !bytes.Equal(data[:5], bip38ConfirmMagic) {
+		return "", ErrBIP38Format
+	}
+	flag, addrHash, ownerEntropy, encPointB := data[5], data[6:10], data[10:18], data[18:51]
+
+	passFactor, err := bip38PassFactor(passphrase, ownerEntropy, flag&bip38FlagLotSeq != 0)
+	if err != nil {
+		return "", err
+	}
+	half1, half2, err := bip38ECDerive(ECBaseMul(passFactor).Serialize(), addrHash, ownerEntropy)
+	if err != nil {
+		return "", err
+	}
+	pointB := []byte{encPointB[0] ^ (half2[31] & 0x01)}
+	pointB = append(pointB, xorBytes(aesDecrypt(encPointB[1:17], half2), half1[:16])...)
+	pointB = append(pointB, xorBytes(aesDecrypt(encPointB[17:33], half2), half1[16:])...)
+	B, err := ParsePubKey(pointB)
+	if err != nil {
+		return "", ErrBIP38Passphrase
+	}
+
+	generated := NewPoint().ECPointMul(passFactor, B)
+	pubKey := generated.SerializeUncompressed()
+	if flag&bip38FlagComp != 0 {
+		pubKey = generated.Serialize()
+	}
+	address := P2PKHAddress(pubKey, net)
+	if !bytes.Equal(checksum([]byte(address)), addrHash) {
+		return "", ErrBIP38Passphrase
+	}
+	return address, nil
+}
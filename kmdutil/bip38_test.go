@@ -0,0 +1,153 @@
+package kmdutil
+
+import (
+	"strings"
+	"testing"
+)
+
+// bip38Tests are the BIP38 test vectors.
+var bip38Tests = []struct {
+	passphrase string
+	encrypted  string
+	wif        string
+	compressed bool
+}{
+	// No compression, no EC multiply.
+	{"TestingOneTwoThree", "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+		"5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR", false},
+	{"Satoshi", "6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq",
+		"5HtasZ6ofTHP6HCwTqTkLDuLQisYPah7aUnSKfC7h4hMUVw2gi5", false},
+	// Compression, no EC multiply.
+	{"TestingOneTwoThree", "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+		"L44B5gGEpqEDRS9vVPz7QT35jcBG2r3CZwSwQ4fCewXAhAhqGVpP", true},
+	{"Satoshi", "6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+		"KwYgW8gcxj1JWJXhPSu4Fqwzfhp5Yfi42mdYmMa4XqK7NJxXUSK7", true},
+	// EC multiply, no compression, no lot/sequence.
+	{"TestingOneTwoThree", "6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+		"5K4caxezwjGCGfnoPTZ8tMcJBLB7Jvyjv4xxeacadhq8nLisLR2", false},
+	{"Satoshi", "6PfLGnQs6VZnrNpmVKfjotbnQuaJK4KZoPFrAjx1JMJUa1Ft8gnf5WxfKd",
+		"5KJ51SgxWaAYR13zd9ReMhJpwrcX47xTJh2D3fGPG9CM8vkv5sH", false},
+	// EC multiply, no compression, lot/sequence.
+	{"MOLON LABE", "6PgNBNNzDkKdhkT6uJntUXwwzQV8Rr2tZcbkDcuC9DZRsS6AtHts4Ypo1j",
+		"5JLdxTtcTHcfYcmJsNVy1v2PMDx432JPoYcBTVVRHpPaxUrdtf8", false},
+	{"ΜΟΛΩΝ ΛΑΒΕ", "6PgGWtx25kUg8QWvwuJAgorN6k9FbE25rv5dMRwu5SKMnfpfVe5mar2ngH",
+		"5KMKKuUmAkiNbA3DazMQiLfDq47qs8MAEThm4yL8R2PhV1ov33D", false},
+}
+
+func TestBIP38Decrypt(t *testing.T) {
+	for _, tt := range bip38Tests {
+		k, compressed, err := BIP38Decrypt(tt.encrypted, tt.passphrase, BTCMainnet)
+		if err != nil {
+			t.Errorf("BIP38Decrypt(%s): %v", tt.encrypted, err)
+			continue
+		}
+		if got := k.WIF(BTCMainnet, compressed); got != tt.wif || compressed != tt.compressed {
+			t.Errorf("BIP38Decrypt(%s) = %s, %v, want %s, %v", tt.encrypted, got, compressed, tt.wif, tt.compressed)
+		}
+	}
+	// One wrong passphrase per mode; each try costs a full scrypt.
+	for _, i := range []int{0, 4} {
+		tt := bip38Tests[i]
+		if _, _, err := BIP38Decrypt(tt.encrypted, tt.passphrase+"x", BTCMainnet); err != ErrBIP38Passphrase {
+			t.Errorf("BIP38Decrypt(%s, wrong passphrase) error = %v, want %v", tt.encrypted, err, ErrBIP38Passphrase)
+		}
+	}
+}
+
+func TestBIP38Encrypt(t *testing.T) {
+	// Only the non-EC-multiply mode is deterministic.
+	for _, tt := range bip38Tests[:4] {
+		k, _, compressed, err := PrivateKeyFromWIF(tt.wif)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := BIP38Encrypt(k, tt.passphrase, BTCMainnet, compressed)
+		if err != nil || got != tt.encrypted {
+			t.Errorf("BIP38Encrypt(%s) = %s, %v, want %s", tt.wif, got, err, tt.encrypted)
+		}
+	}
+}
+
+func TestBIP38Normalization(t *testing.T) {
+	// The BIP38 vector whose passphrase is not in NFC: GREEK UPSILON WITH
+	// HOOK, COMBINING ACUTE ACCENT, NULL, DESERET CAPITAL LETTER LONG I,
+	// PILE OF POO.  Its NFC form starts with U+03D3 instead.
+	const (
+		decomposed = "\u03D2\u0301\u0000\U00010400\U0001F4A9"
+		composed   = "\u03D3\u0000\U00010400\U0001F4A9"
+		encrypted  = "6PRW5o9FLp4gJDDVqJQKJFTpMvdsSGJxMYHtHaQBF3ooa8mwD69bapcDQn"
+		wif        = "5Jajm8eQ22H3pGWLEVCXyvND8dQZhiQhoLJNKjYXk9roUFTMSZ4"
+	)
+	for _, passphrase := range []string{decomposed, composed} {
+		k, _, err := BIP38Decrypt(encrypted, passphrase, BTCMainnet)
+		if err != nil || k.WIF(BTCMainnet, false) != wif {
+			t.Errorf("BIP38Decrypt(%+q) = %s, %v, want %s", passphrase, k.WIF(BTCMainnet, false), err, wif)
+		}
+	}
+	k, _, _, err := PrivateKeyFromWIF(wif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := BIP38Encrypt(k, decomposed, BTCMainnet, false); err != nil || got != encrypted {
+		t.Errorf("BIP38Encrypt(%s) = %s, %v, want %s", wif, got, err, encrypted)
+	}
+}
+
+func TestBIP38VerifyConfirmation(t *testing.T) {
+	tests := []struct {
+		passphrase, confirmation, address string
+	}{
+		{"MOLON LABE", "cfrm38V8aXBn7JWA1ESmFMUn6erxeBGZGAxJPY4e36S9QWkzZKtaVqLNMgnifETYw7BPwWC9aPD",
+			"1Jscj8ALrYu2y9TD8NrpvDBugPedmbj4Yh"},
+		{"ΜΟΛΩΝ ΛΑΒΕ", "cfrm38V8G4qq2ywYEFfWLD5Cc6msj9UwsG2Mj4Z6QdGJAFQpdatZLavkgRd1i4iBMdRngDqDs51",
+			"1Lurmih3KruL4xDB5FmHof38yawNtP9oGf"},
+	}
+	for _, tt := range tests {
+		got, err := BIP38VerifyConfirmation(tt.confirmation, tt.passphrase, BTCMainnet)
+		if err != nil || got != tt.address {
+			t.Errorf("BIP38VerifyConfirmation(%s) = %s, %v, want %s", tt.confirmation, got, err, tt.address)
+		}
+		if _, err := BIP38VerifyConfirmation(tt.confirmation, "wrong", BTCMainnet); err != ErrBIP38Passphrase {
+			t.Errorf("BIP38VerifyConfirmation(wrong passphrase) error = %v, want %v", err, ErrBIP38Passphrase)
+		}
+	}
+}
+
+func TestBIP38IntermediateRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("scrypt round trips are slow")
+	}
+	const passphrase = "TestingOneTwoThree"
+	for _, lotSeq := range []*BIP38LotSequence{nil, {Lot: 263183, Sequence: 1}} {
+		for _, compressed := range []bool{false, true} {
+			intermediate, err := BIP38Intermediate(passphrase, lotSeq)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(intermediate, "passphrase") {
+				t.Errorf("intermediate code %s lacks the passphrase prefix", intermediate)
+			}
+			encrypted, confirmation, address, err := BIP38EncryptFromIntermediate(intermediate, compressed, KMD)
+			if err != nil {
+				t.Fatal(err)
+			}
+			k, gotCompressed, err := BIP38Decrypt(encrypted, passphrase, KMD)
+			if err != nil || gotCompressed != compressed {
+				t.Fatalf("BIP38Decrypt(%s) = %v, %v", encrypted, gotCompressed, err)
+			}
+			pubKey := k.PublicKey().Serialize()
+			if !compressed {
+				pubKey = k.PublicKey().SerializeUncompressed()
+			}
+			if got := P2PKHAddress(pubKey, KMD); got != address {
+				t.Errorf("decrypted key address %s, want %s", got, address)
+			}
+			if got, err := BIP38VerifyConfirmation(confirmation, passphrase, KMD); err != nil || got != address {
+				t.Errorf("BIP38VerifyConfirmation = %s, %v, want %s", got, err, address)
+			}
+		}
+	}
+	if _, err := BIP38Intermediate(passphrase, &BIP38LotSequence{Lot: 1048576}); err != ErrBIP38LotSequence {
+		t.Errorf("BIP38Intermediate(lot 1048576) error = %v, want %v", err, ErrBIP38LotSequence)
+	}
+}
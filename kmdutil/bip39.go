@@ -0,0 +1,249 @@
+package kmdutil
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed" // for the BIP39 wordlist
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// BIP39 mnemonic sentences.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+// The entropy (128 to 256 bits) is followed by the first ENT/32 bits of
+// its SHA-256, and the result is cut into 11-bit indexes into the 2048
+// word list:
+//
+//	words  entropy  checksum
+//	12     128      4
+//	15     160      5
+//	18     192      6
+//	21     224      7
+//	24     256      8
+//
+// The seed is PBKDF2-HMAC-SHA512(mnemonic, "mnemonic" + passphrase, 2048
+// rounds).  BIP39 asks for both strings in Unicode NFKD; that is left to
+// the caller, and ASCII is already in NFKD.
+
+//go:embed bip39_english.txt
+var bip39English string
+
+var (
+	bip39Words     = strings.Fields(bip39English)
+	bip39WordIndex = func() map[string]int {
+		m := make(map[string]int, len(bip39Words))
+		for i, w := range bip39Words {
+			m[w] = i
+		}
+		return m
+	}()
+)
+
+var (
+	// ErrEntropyLength indicates entropy that is not 128 to 256 bits in
+	// steps of 32.
+	ErrEntropyLength = errors.New("entropy must be 16, 20, 24, 28 or 32 bytes")
+
+	// ErrMnemonicLength indicates a mnemonic that is not 12, 15, 18, 21
+	// or 24 words.
+	ErrMnemonicLength = errors.New("mnemonic must be 12, 15, 18, 21 or 24 words")
+
+	// ErrMnemonicChecksum indicates a mnemonic of valid words whose last
+	// word does not carry the checksum of the others, e.g. after two
+	// words were swapped.
+	ErrMnemonicChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// UnknownWordError is returned for a mnemonic word missing from the word
+// list.  Pos is the index of the word, and Suggestions lists close words
+// from SuggestWords.
+type UnknownWordError struct {
+	Pos         int
+	Word        string
+	Suggestions []string
+}
+
+func (e UnknownWordError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown mnemonic word %q at position %d", e.Word, e.Pos+1)
+	}
+	return fmt.Sprintf("unknown mnemonic word %q at position %d, did you mean %s?",
+		e.Word, e.Pos+1, strings.Join(e.Suggestions, ", "))
+}
+
+// BIP39Word returns word i of the English list.
+func BIP39Word(i int) string {
+	return bip39Words[i]
+}
+
+// NewEntropy reads bits/8 bytes of mnemonic entropy from r.  A nil r
+// reads from crypto/rand.
+func NewEntropy(bits int, r io.Reader) ([]byte, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return nil, ErrEntropyLength
+	}
+	if r == nil {
+		r = rand.Reader
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := io.ReadFull(r, entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic returns the mnemonic sentence of entropy.
+func NewMnemonic(entropy []byte) (string, error) {
+	n := len(entropy)
+	if n < 16 || n > 32 || n%4 != 0 {
+		return "", ErrEntropyLength
+	}
+	h := sha256.Sum256(entropy)
+	b := append(append([]byte(nil), entropy...), h[0])
+
+	words := make([]string, (n*8+n/4)/11)
+	for i := range words {
+		// Gather the 11 bits starting at bit i*11 of b.
+		idx := 0
+		for j := i * 11; j < i*11+11; j++ {
+			idx = idx<<1 | int(b[j/8]>>(7-uint(j%8))&1)
+		}
+		words[i] = bip39Words[idx]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// GenerateMnemonic returns a new mnemonic of 12, 15, 18, 21 or 24 words
+// with entropy read from r, or from crypto/rand when r is nil.
+func GenerateMnemonic(words int, r io.Reader) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", ErrMnemonicLength
+	}
+	entropy, err := NewEntropy(words/3*32, r)
+	if err != nil {
+		return "", err
+	}
+	return NewMnemonic(entropy)
+}
+
+// MnemonicToEntropy validates a mnemonic and returns its entropy.  Words
+// may be separated by any white space.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrMnemonicLength
+	}
+	b := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		idx, ok := bip39WordIndex[w]
+		if !ok {
+			return nil, UnknownWordError{i, w, SuggestWords(w, 5)}
+		}
+		for j := 0; j < 11; j++ {
+			if idx>>(10-uint(j))&1 == 1 {
+				k := i*11 + j
+				b[k/8] |= 0x80 >> uint(k%8)
+			}
+		}
+	}
+
+	n := len(words) / 3 * 4 // entropy bytes
+	entropy := b[:n]
+	h := sha256.Sum256(entropy)
+	csBits := uint(n / 4)
+	if b[n]>>(8-csBits) != h[0]>>(8-csBits) {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks the length, words and checksum of a mnemonic.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed validates a mnemonic and stretches it with the optional
+// passphrase into the 64-byte seed used by NewMasterKey.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(sentence), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// SuggestWords returns up to n list words close to word: those it is a
+// prefix of, since the first four letters identify each word, then those
+// within an edit distance of 2, nearest first.
+func SuggestWords(word string, n int) []string {
+	word = strings.ToLower(word)
+	type candidate struct {
+		word string
+		dist int
+	}
+	var cands []candidate
+	for _, w := range bip39Words {
+		switch {
+		case len(word) >= 2 && strings.HasPrefix(w, word):
+			cands = append(cands, candidate{w, 0})
+		case abs(len(w)-len(word)) <= 2:
+			if d := editDistance(word, w); d <= 2 {
+				cands = append(cands, candidate{w, d})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].dist < cands[j].dist })
+
+	var out []string
+	for _, c := range cands {
+		if len(out) == n {
+			break
+		}
+		out = append(out, c.word)
+	}
+	return out
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// editDistance returns the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
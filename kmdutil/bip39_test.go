@@ -0,0 +1,139 @@
+package kmdutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBIP39Vectors(t *testing.T) {
+	// From the reference implementation, all with the passphrase "TREZOR".
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			"9e885d952ad362caeb4efe34a8e91bd2",
+			"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+			"274ddc525802f7c828d8ef7ddbcdc5304e87ac3535913611fbbfa986d0c9e5476c91689f9c8a54fd55bd38606aa6a8595ad213d4c9c9f9aca3fb217069a41028",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon " +
+				"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for _, tt := range tests {
+		entropy := mustHex(t, tt.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil || mnemonic != tt.mnemonic {
+			t.Errorf("NewMnemonic(%s) = %q, %v, want %q", tt.entropy, mnemonic, err, tt.mnemonic)
+		}
+		if got, err := MnemonicToEntropy(tt.mnemonic); err != nil || !bytes.Equal(got, entropy) {
+			t.Errorf("MnemonicToEntropy(%q) = %x, %v, want %s", tt.mnemonic, got, err, tt.entropy)
+		}
+		seed, err := MnemonicToSeed(tt.mnemonic, "TREZOR")
+		if err != nil || hex.EncodeToString(seed) != tt.seed {
+			t.Errorf("MnemonicToSeed(%q) = %x, %v, want %s", tt.mnemonic, seed, err, tt.seed)
+		}
+	}
+
+	// Any white space separates the words.
+	spaced := "  abandon\tabandon abandon abandon abandon abandon\nabandon abandon abandon abandon abandon about "
+	seed, err := MnemonicToSeed(spaced, "TREZOR")
+	if err != nil || hex.EncodeToString(seed) != tests[0].seed {
+		t.Errorf("MnemonicToSeed(spaced) = %x, %v, want %s", seed, err, tests[0].seed)
+	}
+}
+
+func TestBIP39Invalid(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrMnemonicLength},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrMnemonicChecksum},
+		// Swapping two words breaks the checksum.
+		{"legal winner thank year wave sausage worth useful legal winner yellow thank", ErrMnemonicChecksum},
+	}
+	for _, tt := range tests {
+		if err := ValidateMnemonic(tt.mnemonic); err != tt.err {
+			t.Errorf("ValidateMnemonic(%q) error = %v, want %v", tt.mnemonic, err, tt.err)
+		}
+	}
+
+	err := ValidateMnemonic("legal winner thank year wave sausage worth usefull legal winner thank yellow")
+	var wordErr UnknownWordError
+	if !errors.As(err, &wordErr) {
+		t.Fatalf("ValidateMnemonic(typo) error = %v, want UnknownWordError", err)
+	}
+	if wordErr.Pos != 7 || wordErr.Word != "usefull" || len(wordErr.Suggestions) == 0 || wordErr.Suggestions[0] != "useful" {
+		t.Errorf("UnknownWordError = %+v, want position 7 suggesting useful", wordErr)
+	}
+
+	if _, err := NewMnemonic(make([]byte, 17)); err != ErrEntropyLength {
+		t.Errorf("NewMnemonic(17 bytes) error = %v, want %v", err, ErrEntropyLength)
+	}
+	if _, err := GenerateMnemonic(13, nil); err != ErrMnemonicLength {
+		t.Errorf("GenerateMnemonic(13) error = %v, want %v", err, ErrMnemonicLength)
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		m, err := GenerateMnemonic(words, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(strings.Fields(m)); n != words {
+			t.Errorf("GenerateMnemonic(%d) has %d words", words, n)
+		}
+		if err := ValidateMnemonic(m); err != nil {
+			t.Errorf("ValidateMnemonic(%q) error = %v", m, err)
+		}
+	}
+}
+
+func TestSuggestWords(t *testing.T) {
+	tests := []struct {
+		word string
+		n    int
+		want []string
+	}{
+		{"abando", 5, []string{"abandon"}},
+		// Prefix matches come before words within the edit distance.
+		{"zo", 5, []string{"zone", "zoo", "box", "boy", "dog"}},
+		{"zo", 2, []string{"zone", "zoo"}},
+		{"XYLOPHONE", 5, nil},
+	}
+	for _, tt := range tests {
+		if got := SuggestWords(tt.word, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SuggestWords(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
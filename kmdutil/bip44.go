@@ -0,0 +1,180 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BIP44-style account trees.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki
+//	https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+//
+//	m / purpose' / coin_type' / account' / change / address_index
+//
+// The purpose selects the address type of every key below it, change is
+// 0 for receive and 1 for change addresses.
+
+// Purpose is the first level of a BIP44-style path.
+type Purpose uint32
+
+// Purposes and the address types they stand for.
+const (
+	PurposeP2PKH      Purpose = 44 // BIP44: 1..., R...
+	PurposeP2SHP2WPKH Purpose = 49 // BIP49: 3...
+	PurposeP2WPKH     Purpose = 84 // BIP84: bc1q...
+	PurposeP2TR       Purpose = 86 // BIP86: bc1p...
+)
+
+// ErrUnknownPurpose indicates a purpose other than 44, 49, 84 or 86.
+var ErrUnknownPurpose = errors.New("unknown BIP44 purpose")
+
+// Purposes lists the supported purposes in BIP number order.
+var Purposes = []Purpose{PurposeP2PKH, PurposeP2SHP2WPKH, PurposeP2WPKH, PurposeP2TR}
+
+func (p Purpose) String() string {
+	switch p {
+	case PurposeP2PKH:
+		return "BIP44 P2PKH"
+	case PurposeP2SHP2WPKH:
+		return "BIP49 P2SH-P2WPKH"
+	case PurposeP2WPKH:
+		return "BIP84 P2WPKH"
+	case PurposeP2TR:
+		return "BIP86 P2TR"
+	}
+	return fmt.Sprintf("purpose %d", uint32(p))
+}
+
+// AddressType returns the type of the addresses derived under p.
+func (p Purpose) AddressType() AddressType {
+	switch p {
+	case PurposeP2PKH:
+		return AddrP2PKH
+	case PurposeP2SHP2WPKH:
+		return AddrP2SH
+	case PurposeP2WPKH:
+		return AddrP2WPKH
+	case PurposeP2TR:
+		return AddrP2TR
+	}
+	return 0
+}
+
+// ScriptType returns the SLIP-132 script type of the keys derived under
+// p.
+func (p Purpose) ScriptType() ScriptType {
+	switch p {
+	case PurposeP2SHP2WPKH:
+		return ScriptP2SHP2WPKH
+	case PurposeP2WPKH:
+		return ScriptP2WPKH
+	case PurposeP2TR:
+		return ScriptP2TR
+	}
+	return ScriptP2PKH
+}
+
+// Address encodes a compressed public key as the address type of p.  The
+// SegWit purposes fail with ErrNoSegWit on chains without SegWit, such as
+// Komodo.
+func (p Purpose) Address(pubKey []byte, net *Network) (string, error) {
+	switch p {
+	case PurposeP2PKH:
+		if err := checkCompressedPubKey(pubKey); err != nil {
+			return "", err
+		}
+		return P2PKHAddress(pubKey, net), nil
+	case PurposeP2SHP2WPKH:
+		return P2SHP2WPKHAddress(pubKey, net)
+	case PurposeP2WPKH:
+		return P2WPKHAddress(pubKey, net)
+	case PurposeP2TR:
+		// BIP86 commits to no script path, so the output key is the
+		// internal key tweaked with an empty merkle root.
+		if err := checkCompressedPubKey(pubKey); err != nil {
+			return "", err
+		}
+		return P2TRAddress(pubKey[1:], nil, net)
+	}
+	return "", fmt.Errorf("%w: %d", ErrUnknownPurpose, uint32(p))
+}
+
+// AccountPath returns m/purpose'/coin_type'/account' for net.
+func AccountPath(p Purpose, net *Network, account uint32) string {
+	return FormatDerivationPath([]uint32{
+		uint32(p) + HardenedKeyStart,
+		net.HDCoinType + HardenedKeyStart,
+		account + HardenedKeyStart,
+	})
+}
+
+// DeriveAccount derives the account key of purpose p from a master key,
+// using the coin type of the master key's network.  The key carries the
+// script type of p, so a BIP49 or BIP84 account serializes as a ypub or
+// zpub where the network has SegWit, and as an xpub otherwise.
+func (k *ExtendedKey) DeriveAccount(p Purpose, account uint32) (*ExtendedKey, error) {
+	if account >= HardenedKeyStart {
+		return nil, fmt.Errorf("%w: account %d out of range", ErrDerivationPath, account)
+	}
+	a, err := k.Derive(AccountPath(p, k.Net, account))
+	if err != nil {
+		return nil, err
+	}
+	a.Script = p.ScriptType()
+	return a, nil
+}
+
+// HDAddress is one address of an account tree.
+type HDAddress struct {
+	Path    string
+	Address string
+	// WIF is the compressed WIF of the private key, or empty when the
+	// account key is public.  For BIP86 it is the untweaked internal
+	// key, as wallets export it in tr(...) descriptors.
+	WIF string
+}
+
+// AccountAddresses derives count addresses from index start of the
+// receive (change = false) or change chain of an account key returned by
+// DeriveAccount.  A public account key gives addresses without WIFs.  Any
+// other key than a hardened one at depth 3 fails with ErrDerivationPath,
+// since its addresses would not have the paths reported.
+func AccountAddresses(account *ExtendedKey, p Purpose, change bool, start, count uint32) ([]HDAddress, error) {
+	if account.Depth != 3 || account.ChildNumber < HardenedKeyStart {
+		return nil, fmt.Errorf("%w: not an account key: depth %d, child %d",
+			ErrDerivationPath, account.Depth, account.ChildNumber)
+	}
+	var chain uint32
+	if change {
+		chain = 1
+	}
+	branch, err := account.Child(chain)
+	if err != nil {
+		return nil, err
+	}
+	prefix := AccountPath(p, account.Net, account.ChildNumber-HardenedKeyStart)
+	addrs := make([]HDAddress, 0, count)
+	for i := start; i < start+count; i++ {
+		k, err := branch.Child(i)
+		if err != nil {
+			return nil, err
+		}
+		a := HDAddress{Path: fmt.Sprintf("%s/%d/%d", prefix, chain, i)}
+		if a.Address, err = p.Address(k.PubKey(), account.Net); err != nil {
+			return nil, err
+		}
+		if k.IsPrivate() {
+			privKey, err := k.PrivateKey()
+			if err != nil {
+				return nil, err
+			}
+			a.WIF = privKey.WIF(account.Net, true)
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs, nil
+}
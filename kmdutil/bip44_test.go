@@ -0,0 +1,123 @@
+package kmdutil
+
+import (
+	"errors"
+	"testing"
+)
+
+// abandonMaster returns the master key of the "abandon ... about" test
+// mnemonic without a passphrase on net.
+func abandonMaster(t *testing.T, net *Network) *ExtendedKey {
+	t.Helper()
+	seed, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := NewMasterKey(seed, net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return master
+}
+
+func TestAccountAddresses(t *testing.T) {
+	// The first receive address of account 0, from the test vectors of
+	// each BIP.
+	tests := []struct {
+		p       Purpose
+		path    string
+		account string
+		receive string
+		change  string
+	}{
+		{PurposeP2PKH, "m/44'/0'/0'/0/0",
+			"xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj",
+			"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", ""},
+		{PurposeP2SHP2WPKH, "m/49'/0'/0'/0/0",
+			"ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP",
+			"37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf", ""},
+		{PurposeP2WPKH, "m/84'/0'/0'/0/0",
+			"zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs",
+			"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el"},
+		{PurposeP2TR, "m/86'/0'/0'/0/0",
+			"xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ",
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+			"bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+	}
+	master := abandonMaster(t, BTCMainnet)
+	for _, tt := range tests {
+		account, err := master.DeriveAccount(tt.p, 0)
+		if err != nil {
+			t.Fatalf("%s: DeriveAccount: %v", tt.p, err)
+		}
+		if got := account.Neuter().String(); got != tt.account {
+			t.Errorf("%s: account key = %s, want %s", tt.p, got, tt.account)
+		}
+		addrs, err := AccountAddresses(account, tt.p, false, 0, 2)
+		if err != nil {
+			t.Fatalf("%s: AccountAddresses: %v", tt.p, err)
+		}
+		if addrs[0].Path != tt.path || addrs[0].Address != tt.receive {
+			t.Errorf("%s: first address = %s %s, want %s %s", tt.p, addrs[0].Path, addrs[0].Address, tt.path, tt.receive)
+		}
+
+		// The public account key derives the same addresses.
+		pubAddrs, err := AccountAddresses(account.Neuter(), tt.p, false, 0, 2)
+		if err != nil {
+			t.Fatalf("%s: AccountAddresses(public): %v", tt.p, err)
+		}
+		for i := range addrs {
+			if pubAddrs[i].Address != addrs[i].Address || pubAddrs[i].WIF != "" {
+				t.Errorf("%s: public address %d = %+v, want %s without WIF", tt.p, i, pubAddrs[i], addrs[i].Address)
+			}
+		}
+
+		if tt.change != "" {
+			change, err := AccountAddresses(account, tt.p, true, 0, 1)
+			if err != nil || change[0].Address != tt.change {
+				t.Errorf("%s: first change address = %+v, %v, want %s", tt.p, change, err, tt.change)
+			}
+		}
+	}
+}
+
+func TestAccountAddressesKomodo(t *testing.T) {
+	account, err := abandonMaster(t, KMD).DeriveAccount(PurposeP2PKH, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := AccountAddresses(account, PurposeP2PKH, false, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HDAddress{
+		Path:    "m/44'/141'/0'/0/0",
+		Address: "RW8gfgpCUdgZbkPAs1uJQF2S9681JVkGRi",
+		WIF:     "UsUUoXgoewegNcnKnTfxUkqWuqsWNSYCYwDci51HcJRMq69nLzGh",
+	}
+	if addrs[0] != want {
+		t.Errorf("first KMD address = %+v, want %+v", addrs[0], want)
+	}
+
+	for _, p := range []Purpose{PurposeP2SHP2WPKH, PurposeP2WPKH, PurposeP2TR} {
+		if _, err := p.Address(account.PubKey(), KMD); !errors.Is(err, ErrNoSegWit) {
+			t.Errorf("%s on KMD error = %v, want %v", p, err, ErrNoSegWit)
+		}
+	}
+	if _, err := Purpose(45).Address(account.PubKey(), KMD); !errors.Is(err, ErrUnknownPurpose) {
+		t.Errorf("purpose 45 error = %v, want %v", err, ErrUnknownPurpose)
+	}
+	if _, err := account.DeriveAccount(PurposeP2PKH, HardenedKeyStart); !errors.Is(err, ErrDerivationPath) {
+		t.Errorf("DeriveAccount(2^31) error = %v, want %v", err, ErrDerivationPath)
+	}
+
+	// Keys that are not hardened depth 3 keys would get wrong paths such
+	// as m/44'/141'/2147483653'.
+	parent, _ := abandonMaster(t, KMD).Derive("m/44'/141'")
+	unhardened, _ := parent.Neuter().Child(5)
+	for _, k := range []*ExtendedKey{unhardened, parent} {
+		if _, err := AccountAddresses(k, PurposeP2PKH, false, 0, 1); !errors.Is(err, ErrDerivationPath) {
+			t.Errorf("AccountAddresses(depth %d, child %d) error = %v, want %v", k.Depth, k.ChildNumber, err, ErrDerivationPath)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package kmdutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bitcoin Cash CashAddr addresses.  See:
+//
+//	https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+//
+// CashAddr uses the Bech32 charset, but a 40-bit BCH checksum, a ':'
+// separator and a version byte holding the address type and hash size.
+
+var (
+	// ErrCashAddrChecksum indicates a CashAddr whose checksum does not
+	// match.
+	ErrCashAddrChecksum = errors.New("cashaddr: invalid checksum")
+
+	// ErrCashAddrVersion indicates an unknown address type or a hash size
+	// that disagrees with the version byte.
+	ErrCashAddrVersion = errors.New("cashaddr: invalid version byte")
+
+	// ErrNoCashAddr is returned for a network without a CashAddr prefix.
+	ErrNoCashAddr = errors.New("network does not support cashaddr")
+)
+
+// cashAddrSizes maps the size code of the version byte to the hash size.
+var cashAddrSizes = [8]int{20, 24, 28, 32, 40, 48, 56, 64}
+
+func cashAddrPolymod(values []byte) uint64 {
+	gen := [5]uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+	c := uint64(1)
+	for _, v := range values {
+		c0 := byte(c >> 35)
+		c = (c&0x07ffffffff)<<5 ^ uint64(v)
+		for i := 0; i < 5; i++ {
+			if (c0>>uint(i))&1 == 1 {
+				c ^= gen[i]
+			}
+		}
+	}
+	return c ^ 1
+}
+
+// cashAddrPrefixExpand returns the lower 5 bits of each prefix character
+// followed by the zero separator.
+func cashAddrPrefixExpand(prefix string) []byte {
+	out := make([]byte, 0, len(prefix)+1)
+	for i := 0; i < len(prefix); i++ {
+		out = append(out, prefix[i]&31)
+	}
+	return append(out, 0)
+}
+
+// EncodeCashAddr encodes a P2PKH or P2SH hash under prefix, e.g.
+// "bitcoincash".
+func EncodeCashAddr(prefix string, scriptHash bool, hash []byte) (string, error) {
+	size := -1
+	for code, n := range cashAddrSizes {
+		if n == len(hash) {
+			size = code
+		}
+	}
+	if size < 0 {
+		return "", ErrCashAddrVersion
+	}
+	version := byte(size)
+	if scriptHash {
+		version |= 1 << 3
+	}
+	data, err := ConvertBits(append([]byte{version}, hash...), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	prefix = strings.ToLower(prefix)
+	values := append(cashAddrPrefixExpand(prefix), data...)
+	mod := cashAddrPolymod(append(values, 0, 0, 0, 0, 0, 0, 0, 0))
+
+	var sb strings.Builder
+	sb.Grow(len(prefix) + 1 + len(data) + 8)
+	sb.WriteString(prefix)
+	sb.WriteByte(':')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 8; i++ {
+		sb.WriteByte(bech32Charset[mod>>uint(5*(7-i))&31])
+	}
+	return sb.String(), nil
+}
+
+// DecodeCashAddr decodes a CashAddr.  The prefix may be left out of addr,
+// in which case defaultPrefix is used for the checksum.
+func DecodeCashAddr(addr, defaultPrefix string) (prefix string, scriptHash bool, hash []byte, err error) {
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return "", false, nil, ErrBech32MixedCase
+	}
+	addr = strings.ToLower(addr)
+	prefix, payload := strings.ToLower(defaultPrefix), addr
+	if sep := strings.LastIndexByte(addr, ':'); sep >= 0 {
+		prefix, payload = addr[:sep], addr[sep+1:]
+	}
+	if prefix == "" || len(payload) < 8 {
+		return "", false, nil, ErrBech32Separator
+	}
+
+	values := make([]byte, len(payload))
+	for i := 0; i < len(payload); i++ {
+		v := bech32Index[payload[i]]
+		if v == 0xff {
+			return "", false, nil, Bech32CharError{len(addr) - len(payload) + i, payload[i]}
+		}
+		values[i] = v
+	}
+	if cashAddrPolymod(append(cashAddrPrefixExpand(prefix), values...)) != 0 {
+		return "", false, nil, ErrCashAddrChecksum
+	}
+
+	data, err := ConvertBits(values[:len(values)-8], 5, 8, false)
+	if err != nil {
+		return "", false, nil, err
+	}
+	if len(data) < 1 {
+		return "", false, nil, ErrCashAddrVersion
+	}
+	version, hash := data[0], data[1:]
+	if version&0x80 != 0 || version>>3 > 1 || cashAddrSizes[version&7] != len(hash) {
+		return "", false, nil, ErrCashAddrVersion
+	}
+	return prefix, version>>3 == 1, hash, nil
+}
+
+// cashAddrPrefix returns the CashAddr prefix of net, or ErrNoCashAddr.
+func cashAddrPrefix(net *Network) (string, error) {
+	if net.CashAddrPrefix == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoCashAddr, net.Name)
+	}
+	return net.CashAddrPrefix, nil
+}
+
+// P2PKHCashAddress returns the CashAddr form of P2PKHAddress.
+func P2PKHCashAddress(pubKey []byte, net *Network) (string, error) {
+	prefix, err := cashAddrPrefix(net)
+	if err != nil {
+		return "", err
+	}
+	return EncodeCashAddr(prefix, false, Hash160(pubKey))
+}
+
+// LegacyToCashAddr converts a Base58Check P2PKH or P2SH address of net
+// to a CashAddr with the prefix of net.  The address must carry net's own
+// version bytes, so a Komodo R... address is rejected rather than
+// re-encoded as a Bitcoin Cash address paying to the same hash.
+func LegacyToCashAddr(legacy string, net *Network) (string, error) {
+	prefix, err := cashAddrPrefix(net)
+	if err != nil {
+		return "", err
+	}
+	data, err := base58CheckDecode(legacy, 1)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range []struct {
+		version    []byte
+		scriptHash bool
+	}{
+		{net.PubKeyHashAddrID, false},
+		{net.ScriptHashAddrID, true},
+	} {
+		if len(data) == len(p.version)+20 && bytes.HasPrefix(data, p.version) {
+			return EncodeCashAddr(prefix, p.scriptHash, data[len(p.version):])
+		}
+	}
+	return "", fmt.Errorf("%w: %s address %s", ErrUnknownAddressPrefix, net, legacy)
+}
+
+// CashAddrToLegacy converts a CashAddr of net to a Base58Check address
+// with net's version bytes.
+func CashAddrToLegacy(addr string, net *Network) (string, error) {
+	prefix, err := cashAddrPrefix(net)
+	if err != nil {
+		return "", err
+	}
+	got, scriptHash, hash, err := DecodeCashAddr(addr, prefix)
+	if err != nil {
+		return "", err
+	}
+	if got != prefix {
+		return "", fmt.Errorf("%w: cashaddr prefix %s", ErrUnknownAddressPrefix, got)
+	}
+	if len(hash) != 20 {
+		return "", ErrCashAddrVersion
+	}
+	if scriptHash {
+		return Base58CheckEncodePrefix(hash, net.ScriptHashAddrID), nil
+	}
+	return Base58CheckEncodePrefix(hash, net.PubKeyHashAddrID), nil
+}
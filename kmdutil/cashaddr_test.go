@@ -0,0 +1,93 @@
+package kmdutil
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cashAddrTests are the legacy/CashAddr pairs of the CashAddr spec.
+var cashAddrTests = []struct {
+	legacy, cashAddr string
+}{
+	{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+	{"1KXrWXciRDZUpQwQmuM1DbwsKDLYAYsVLR", "bitcoincash:qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy"},
+	{"16w1D5WRVKJuZUsSRzdLp9w3YGcgoxDXb", "bitcoincash:qqq3728yw0y47sqn6l2na30mcw6zm78dzqre909m2r"},
+	{"3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC", "bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq"},
+	{"3LDsS579y7sruadqu11beEJoTjdFiFCdX4", "bitcoincash:pr95sy3j9xwd2ap32xkykttr4cvcu7as4yc93ky28e"},
+	{"31nwvkZwyPdgzjBJZXfDmSWsC4ZLKpYyUw", "bitcoincash:pqq3728yw0y47sqn6l2na30mcw6zm78dzq5ucqzc37"},
+}
+
+func TestLegacyCashAddr(t *testing.T) {
+	for _, tt := range cashAddrTests {
+		if got, err := LegacyToCashAddr(tt.legacy, BCH); err != nil || got != tt.cashAddr {
+			t.Errorf("LegacyToCashAddr(%s) = %s, %v, want %s", tt.legacy, got, err, tt.cashAddr)
+		}
+		if got, err := CashAddrToLegacy(tt.cashAddr, BCH); err != nil || got != tt.legacy {
+			t.Errorf("CashAddrToLegacy(%s) = %s, %v, want %s", tt.cashAddr, got, err, tt.legacy)
+		}
+		// The prefix may be left out, and the address given in upper case.
+		if got, err := CashAddrToLegacy(tt.cashAddr[len("bitcoincash:"):], BCH); err != nil || got != tt.legacy {
+			t.Errorf("CashAddrToLegacy(no prefix) = %s, %v, want %s", got, err, tt.legacy)
+		}
+	}
+}
+
+func TestLegacyToCashAddrWrongNetwork(t *testing.T) {
+	kmdAddr := P2PKHAddress(CurveG().Serialize(), KMD)
+	if _, err := LegacyToCashAddr(kmdAddr, BCH); !errors.Is(err, ErrUnknownAddressPrefix) {
+		t.Errorf("LegacyToCashAddr(%s, bch) error = %v, want %v", kmdAddr, err, ErrUnknownAddressPrefix)
+	}
+	if _, err := LegacyToCashAddr(cashAddrTests[0].legacy, KMD); !errors.Is(err, ErrNoCashAddr) {
+		t.Errorf("LegacyToCashAddr(kmd) error = %v, want %v", err, ErrNoCashAddr)
+	}
+	wif := "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"
+	if _, err := LegacyToCashAddr(wif, BCH); !errors.Is(err, ErrUnknownAddressPrefix) {
+		t.Errorf("LegacyToCashAddr(WIF) error = %v, want %v", err, ErrUnknownAddressPrefix)
+	}
+}
+
+func TestDecodeCashAddr(t *testing.T) {
+	hash := mustHex(t, "f5bf48b397dae70be82b3cca4793f8eb2b6cdac9")
+	tests := []struct {
+		addr       string
+		prefix     string
+		scriptHash bool
+	}{
+		{"bitcoincash:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg2", "bitcoincash", false},
+		{"bchtest:pr6m7j9njldwwzlg9v7v53unlr4jkmx6eyvwc0uz5t", "bchtest", true},
+		{"pref:pr6m7j9njldwwzlg9v7v53unlr4jkmx6ey65nvtks5", "pref", true},
+		{"BITCOINCASH:QR6M7J9NJLDWWZLG9V7V53UNLR4JKMX6EYLEP8EKG2", "bitcoincash", false},
+	}
+	for _, tt := range tests {
+		prefix, scriptHash, got, err := DecodeCashAddr(tt.addr, "")
+		if err != nil || prefix != tt.prefix || scriptHash != tt.scriptHash || !bytes.Equal(got, hash) {
+			t.Errorf("DecodeCashAddr(%s) = %s, %v, %x, %v", tt.addr, prefix, scriptHash, got, err)
+		}
+		if enc, err := EncodeCashAddr(tt.prefix, tt.scriptHash, hash); err != nil || !strings.EqualFold(enc, tt.addr) {
+			t.Errorf("EncodeCashAddr(%s) = %s, %v, want %s", tt.prefix, enc, err, tt.addr)
+		}
+	}
+}
+
+func TestDecodeCashAddrInvalid(t *testing.T) {
+	tests := []struct {
+		addr string
+		want error
+	}{
+		{"bitcoincash:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg3", ErrCashAddrChecksum},
+		{"bchtest:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg2", ErrCashAddrChecksum},
+		{"prefix:0r6m7j9njldwwzlg9v7v53unlr4jkmx6ey3qnjwsrf", ErrCashAddrVersion},
+		{"bitcoincash:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekG2", ErrBech32MixedCase},
+		{"qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg2", ErrBech32Separator},
+	}
+	for _, tt := range tests {
+		if _, _, _, err := DecodeCashAddr(tt.addr, ""); err != tt.want {
+			t.Errorf("DecodeCashAddr(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+	if _, err := EncodeCashAddr("bitcoincash", false, make([]byte, 21)); err != ErrCashAddrVersion {
+		t.Errorf("EncodeCashAddr(21 bytes) error = %v, want %v", err, ErrCashAddrVersion)
+	}
+}
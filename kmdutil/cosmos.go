@@ -0,0 +1,140 @@
+package kmdutil
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+)
+
+// Cosmos-SDK bech32 addresses and public keys.  See:
+//
+//	https://docs.cosmos.network/main/learn/beginner/accounts#addresses
+//	https://github.com/cosmos/cosmos-sdk/blob/main/docs/architecture/adr-028-public-key-addresses.md
+//
+// Account and validator operator addresses are Hash160 of the compressed
+// secp256k1 key, as for P2PKH, encoded in Bech32 under a chain's HRP.
+// Consensus (Tendermint) keys are usually ed25519, whose address is the
+// first 20 bytes of SHA256(pubkey).
+
+// HRP suffixes appended to a chain's account HRP, e.g. "cosmos" gives
+// "cosmosvaloper" and "cosmosvalconspub".
+const (
+	CosmosPubSuffix        = "pub"
+	CosmosValOperSuffix    = "valoper"
+	CosmosValOperPubSuffix = "valoperpub"
+	CosmosValConsSuffix    = "valcons"
+	CosmosValConsPubSuffix = "valconspub"
+)
+
+// Amino prefixes of the legacy bech32 public key encoding: the type
+// prefix followed by the key length.
+var (
+	aminoSecp256k1PubKey = []byte{0xeb, 0x5a, 0xe9, 0x87, 0x21}
+	aminoEd25519PubKey   = []byte{0x16, 0x24, 0xde, 0x64, 0x20}
+)
+
+// ErrCosmosAddressLength is returned when a decoded Cosmos address is not
+// 20 bytes long.
+var ErrCosmosAddressLength = errors.New("cosmos address must be 20 bytes")
+
+func cosmosEncode(hrp string, data []byte) (string, error) {
+	conv, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return Bech32Encode(hrp, conv, Bech32)
+}
+
+// CosmosAccAddress returns the account address hrp1... of a compressed
+// secp256k1 public key, e.g. cosmos1... for hrp "cosmos".
+func CosmosAccAddress(pubKey []byte, hrp string) (string, error) {
+	if err := checkCompressedPubKey(pubKey); err != nil {
+		return "", err
+	}
+	return cosmosEncode(hrp, Hash160(pubKey))
+}
+
+// CosmosValOperAddress returns the validator operator address
+// hrpvaloper1... of the operator's account key.
+func CosmosValOperAddress(pubKey []byte, hrp string) (string, error) {
+	return CosmosAccAddress(pubKey, hrp+CosmosValOperSuffix)
+}
+
+// consAddress returns the Tendermint address of a consensus key: 32-byte
+// ed25519 or 33-byte compressed secp256k1.
+func consAddress(consPubKey []byte) ([]byte, error) {
+	if len(consPubKey) == ed25519.PublicKeySize {
+		h := sha256.Sum256(consPubKey)
+		return h[:20], nil
+	}
+	if err := checkCompressedPubKey(consPubKey); err != nil {
+		return nil, err
+	}
+	return Hash160(consPubKey), nil
+}
+
+// CosmosValConsAddress returns the consensus address hrpvalcons1... of a
+// validator's ed25519 or secp256k1 consensus key.
+func CosmosValConsAddress(consPubKey []byte, hrp string) (string, error) {
+	addr, err := consAddress(consPubKey)
+	if err != nil {
+		return "", err
+	}
+	return cosmosEncode(hrp+CosmosValConsSuffix, addr)
+}
+
+// aminoPubKey returns the Amino encoding of a 32-byte ed25519 or 33-byte
+// secp256k1 public key.
+func aminoPubKey(pubKey []byte) ([]byte, error) {
+	prefix := aminoEd25519PubKey
+	if len(pubKey) != ed25519.PublicKeySize {
+		if err := checkCompressedPubKey(pubKey); err != nil {
+			return nil, err
+		}
+		prefix = aminoSecp256k1PubKey
+	}
+	return append(append([]byte{}, prefix...), pubKey...), nil
+}
+
+// CosmosPubKey returns the legacy bech32 account public key
+// hrppub1addwnpepq... of a compressed secp256k1 key.
+func CosmosPubKey(pubKey []byte, hrp string) (string, error) {
+	if err := checkCompressedPubKey(pubKey); err != nil {
+		return "", err
+	}
+	b, err := aminoPubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return cosmosEncode(hrp+CosmosPubSuffix, b)
+}
+
+// CosmosValConsPubKey returns the bech32 consensus public key
+// hrpvalconspub1zcjduepq... of an ed25519 (or secp256k1) consensus key.
+func CosmosValConsPubKey(consPubKey []byte, hrp string) (string, error) {
+	b, err := aminoPubKey(consPubKey)
+	if err != nil {
+		return "", err
+	}
+	return cosmosEncode(hrp+CosmosValConsPubSuffix, b)
+}
+
+// DecodeCosmosAddress returns the HRP and 20-byte address of any Cosmos
+// account, operator or consensus address.
+func DecodeCosmosAddress(addr string) (hrp string, hash []byte, err error) {
+	hrp, data, variant, err := Bech32Decode(addr)
+	if err != nil {
+		return "", nil, err
+	}
+	if variant != Bech32 {
+		return "", nil, ErrBech32Checksum
+	}
+	hash, err = ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(hash) != 20 {
+		return "", nil, ErrCosmosAddressLength
+	}
+	return hrp, hash, nil
+}
@@ -0,0 +1,80 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestCosmosAccAddress(t *testing.T) {
+	// The public key at m/44'/118'/0'/0/0 of the BIP39 "abandon ... about"
+	// mnemonic, whose address gaiad and Keplr show.
+	pubKey := mustHex(t, "024f4e2ad99c34d60b9ba6283c9431a8418af8673212961f97a77b6377fcd05b62")
+	const want = "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4"
+	addr, err := CosmosAccAddress(pubKey, "cosmos")
+	if err != nil || addr != want {
+		t.Errorf("CosmosAccAddress = %s, %v, want %s", addr, err, want)
+	}
+	hrp, hash, err := DecodeCosmosAddress(addr)
+	if err != nil || hrp != "cosmos" || !bytes.Equal(hash, Hash160(pubKey)) {
+		t.Errorf("DecodeCosmosAddress(%s) = %s, %x, %v", addr, hrp, hash, err)
+	}
+
+	// The operator address is the same hash under another HRP.
+	valoper, err := CosmosValOperAddress(pubKey, "cosmos")
+	if err != nil || !strings.HasPrefix(valoper, "cosmosvaloper1") {
+		t.Fatalf("CosmosValOperAddress = %s, %v", valoper, err)
+	}
+	if _, h, _ := DecodeCosmosAddress(valoper); !bytes.Equal(h, hash) {
+		t.Errorf("valoper hash %x, want %x", h, hash)
+	}
+
+	pub, err := CosmosPubKey(pubKey, "cosmos")
+	if err != nil || !strings.HasPrefix(pub, "cosmospub1addwnpepq") {
+		t.Errorf("CosmosPubKey = %s, %v, want cosmospub1addwnpepq...", pub, err)
+	}
+	if _, err := CosmosAccAddress(CurveG().SerializeUncompressed(), "cosmos"); err != ErrUncompressedPubKey {
+		t.Errorf("CosmosAccAddress(uncompressed) error = %v, want %v", err, ErrUncompressedPubKey)
+	}
+}
+
+func TestCosmosConsensusKey(t *testing.T) {
+	pub := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	addr, err := CosmosValConsAddress(pub, "cosmos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, hash, err := DecodeCosmosAddress(addr)
+	if err != nil || hrp != "cosmosvalcons" || !bytes.Equal(hash, sha256Sum(pub)[:20]) {
+		t.Errorf("DecodeCosmosAddress(%s) = %s, %x, %v", addr, hrp, hash, err)
+	}
+	consPub, err := CosmosValConsPubKey(pub, "cosmos")
+	if err != nil || !strings.HasPrefix(consPub, "cosmosvalconspub1zcjduepq") {
+		t.Errorf("CosmosValConsPubKey = %s, %v, want cosmosvalconspub1zcjduepq...", consPub, err)
+	}
+}
+
+func TestDecodeCosmosAddressInvalid(t *testing.T) {
+	// A P2WSH address is valid Bech32 but decodes to 33 bytes.
+	p2wsh := "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3"
+	tests := []struct {
+		addr string
+		want error
+	}{
+		{"cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal5", ErrBech32Checksum},
+		{p2wsh, ErrCosmosAddressLength},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", ErrBech32Checksum},
+	}
+	for _, tt := range tests {
+		if _, _, err := DecodeCosmosAddress(tt.addr); err != tt.want {
+			t.Errorf("DecodeCosmosAddress(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+}
+
+func sha256Sum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
@@ -0,0 +1,116 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Ethereum/EVM addresses from secp256k1 keys.  See:
+//
+//	https://ethereum.github.io/yellowpaper/paper.pdf - Appendix F, eq. 284.
+//	https://github.com/ethereum/EIPs/blob/master/EIPS/eip-55.md
+//
+// The address is the last 20 bytes of Keccak-256(x || y), the 64-byte
+// uncompressed public key without the 0x04 prefix.  Keccak-256 is the
+// original submission, not the final SHA3-256 standard.
+
+var (
+	// ErrEVMAddressFormat indicates a string that is not 0x followed by
+	// 40 hex digits.
+	ErrEVMAddressFormat = errors.New("invalid EVM address format")
+
+	// ErrEVMChecksum indicates a mixed-case address whose letter cases
+	// do not match its EIP-55 checksum.
+	ErrEVMChecksum = errors.New("invalid EIP-55 checksum")
+)
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// evmAddressBytes returns the last 20 bytes of Keccak-256(x || y).
+func evmAddressBytes(P Point) []byte {
+	return keccak256(P.SerializeUncompressed()[1:])[12:]
+}
+
+// EVMAddress returns the EIP-55 checksummed address of public key P.
+func EVMAddress(P Point) string {
+	return eip55(hex.EncodeToString(evmAddressBytes(P)))
+}
+
+// EVMAddressFromPubKey is EVMAddress for a serialized public key, either
+// compressed or uncompressed.
+func EVMAddressFromPubKey(pubKey []byte) (string, error) {
+	P, err := ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return EVMAddress(P), nil
+}
+
+// eip55 capitalizes each letter of the lower case hex address whose
+// nibble in Keccak-256(address) is 8 or more.
+func eip55(lower string) string {
+	hash := keccak256([]byte(lower))
+	b := []byte(lower)
+	for i, c := range b {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(b)
+}
+
+// evmHex returns addr without its 0x or 0X prefix, and whether it had
+// one.
+func evmHex(addr string) (string, bool) {
+	if len(addr) >= 2 && addr[0] == '0' && (addr[1] == 'x' || addr[1] == 'X') {
+		return addr[2:], true
+	}
+	return addr, false
+}
+
+// EIP55Checksum returns the checksummed form of an EVM address given in
+// any letter case, with or without the 0x (or 0X) prefix.
+func EIP55Checksum(addr string) (string, error) {
+	s, _ := evmHex(addr)
+	if len(s) != 40 {
+		return "", ErrEVMAddressFormat
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", ErrEVMAddressFormat
+	}
+	return eip55(strings.ToLower(s)), nil
+}
+
+// ValidateEVMAddress checks the format of addr, which must start with 0x
+// (or 0X), and, when it is mixed case, its EIP-55 checksum.  All lower or
+// all upper case addresses carry no checksum and are accepted as is.
+func ValidateEVMAddress(addr string) error {
+	s, ok := evmHex(addr)
+	if !ok {
+		return ErrEVMAddressFormat
+	}
+	sum, err := EIP55Checksum(addr)
+	if err != nil {
+		return err
+	}
+	if s == strings.ToLower(s) || s == strings.ToUpper(s) {
+		return nil
+	}
+	if s != sum[2:] {
+		return ErrEVMChecksum
+	}
+	return nil
+}
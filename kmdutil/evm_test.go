@@ -0,0 +1,87 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// eip55Tests are the checksummed addresses from EIP-55.
+var eip55Tests = []string{
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestKeccak256(t *testing.T) {
+	const want = "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+	if got := hex.EncodeToString(keccak256(nil)); got != want {
+		t.Errorf("keccak256(\"\") = %s, want %s", got, want)
+	}
+}
+
+func TestEIP55Checksum(t *testing.T) {
+	for _, want := range eip55Tests {
+		// The all-caps and all-lower vectors are their own checksum
+		// only because every letter hashes the same way.
+		for _, in := range []string{want, strings.ToLower(want), "0X" + strings.ToUpper(want[2:]), want[2:]} {
+			if got, err := EIP55Checksum(in); err != nil || got != want {
+				t.Errorf("EIP55Checksum(%s) = %s, %v, want %s", in, got, err, want)
+			}
+		}
+		for _, in := range []string{want, "0X" + want[2:]} {
+			if err := ValidateEVMAddress(in); err != nil {
+				t.Errorf("ValidateEVMAddress(%s): %v", in, err)
+			}
+		}
+	}
+}
+
+func TestValidateEVMAddressInvalid(t *testing.T) {
+	tests := []struct {
+		addr string
+		want error
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", ErrEVMChecksum},
+		{"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ErrEVMChecksum},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ErrEVMAddressFormat},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", ErrEVMAddressFormat},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", ErrEVMAddressFormat},
+		{"0x0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ErrEVMAddressFormat},
+	}
+	for _, tt := range tests {
+		if err := ValidateEVMAddress(tt.addr); err != tt.want {
+			t.Errorf("ValidateEVMAddress(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+	// No checksum to check in a single case address.
+	for _, addr := range []string{
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+	} {
+		if err := ValidateEVMAddress(addr); err != nil {
+			t.Errorf("ValidateEVMAddress(%s): %v", addr, err)
+		}
+	}
+}
+
+func TestEVMAddress(t *testing.T) {
+	const want = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf" // private key 1
+	if got := EVMAddress(ECBaseMul(big.NewInt(1))); got != want {
+		t.Errorf("EVMAddress(G) = %s, want %s", got, want)
+	}
+	for _, pubKey := range [][]byte{CurveG().Serialize(), CurveG().SerializeUncompressed()} {
+		if got, err := EVMAddressFromPubKey(pubKey); err != nil || got != want {
+			t.Errorf("EVMAddressFromPubKey(%x) = %s, %v, want %s", pubKey, got, err, want)
+		}
+	}
+	if _, err := EVMAddressFromPubKey(make([]byte, 33)); err == nil {
+		t.Error("EVMAddressFromPubKey accepted an invalid public key")
+	}
+}
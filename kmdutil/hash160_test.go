@@ -0,0 +1,30 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestHash160(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "b472a266d0bd89c13706a4132ccfb16f7c3b9fcb"},
+		// The compressed and uncompressed public keys of private key 1.
+		{"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			"91b24bf9f5288532960ac687abb035127b1d28a5"},
+	}
+	for _, tt := range tests {
+		in, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(Hash160(in)); got != tt.want {
+			t.Errorf("Hash160(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
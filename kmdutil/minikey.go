@@ -0,0 +1,82 @@
+package kmdutil
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// Casascius mini private keys.  See:
+//
+//	https://en.bitcoin.it/wiki/Mini_private_key_format
+//
+// A mini key is 'S' followed by 21 or 29 characters of Bitcoin's Base58
+// alphabet.  It is well formed when SHA256(key + "?") starts with 0x00,
+// and its private key is SHA256(key).
+
+var (
+	// ErrMiniKeyFormat indicates a string that is not 'S' followed by 21
+	// or 29 Base58 characters.
+	ErrMiniKeyFormat = errors.New("invalid mini private key format")
+
+	// ErrMiniKeyCheck indicates a mini key whose SHA256(key + "?") does
+	// not start with 0x00, usually a typo.
+	ErrMiniKeyCheck = errors.New("mini private key check failed")
+)
+
+func miniKeyWellFormed(key string) bool {
+	h := sha256.Sum256([]byte(key + "?"))
+	return h[0] == 0x00
+}
+
+// ParseMiniKey validates a mini key and returns its private key.
+func ParseMiniKey(key string) (PrivateKey, error) {
+	if len(key) != 22 && len(key) != 30 || key[0] != 'S' {
+		return PrivateKey{}, ErrMiniKeyFormat
+	}
+	for i := 1; i < len(key); i++ {
+		if BitcoinAlphabet.index[key[i]] == 0xff {
+			return PrivateKey{}, ErrMiniKeyFormat
+		}
+	}
+	if !miniKeyWellFormed(key) {
+		return PrivateKey{}, ErrMiniKeyCheck
+	}
+	h := sha256.Sum256([]byte(key))
+	return PrivateKeyFromBytes(h[:])
+}
+
+// GenerateMiniKey draws random candidates of length 22 or 30 from
+// entropy until one is well formed, which takes 256 tries on average.  A
+// nil entropy reads from crypto/rand.
+func GenerateMiniKey(length int, entropy io.Reader) (string, PrivateKey, error) {
+	if length != 22 && length != 30 {
+		return "", PrivateKey{}, ErrMiniKeyFormat
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	key := make([]byte, length)
+	key[0] = 'S'
+	buf := make([]byte, 64)
+	for {
+		// Rejecting bytes of 232 and above keeps every character equally
+		// likely, since 232 = 4*58.
+		for i := 1; i < length; {
+			if _, err := io.ReadFull(entropy, buf); err != nil {
+				return "", PrivateKey{}, err
+			}
+			for _, b := range buf {
+				if b < 232 && i < length {
+					key[i] = BitcoinAlphabet.chars[b%58]
+					i++
+				}
+			}
+		}
+		if miniKeyWellFormed(string(key)) {
+			k, err := ParseMiniKey(string(key))
+			return string(key), k, err
+		}
+	}
+}
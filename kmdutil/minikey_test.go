@@ -0,0 +1,67 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestParseMiniKey(t *testing.T) {
+	// The example keys of the Bitcoin wiki.
+	const key = "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy"
+	k, err := ParseMiniKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := k.Hex(), "4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab"; got != want {
+		t.Errorf("ParseMiniKey(%s) = %s, want %s", key, got, want)
+	}
+	addr := P2PKHAddress(k.PublicKey().SerializeUncompressed(), BTCMainnet)
+	if want := "1CciesT23BNionJeXrbxmjc7ywfiyM4oLW"; addr != want {
+		t.Errorf("address of %s = %s, want %s", key, addr, want)
+	}
+	if _, err := ParseMiniKey("SzavMBLoXU6kDrqtUVmffv"); err != nil {
+		t.Errorf("ParseMiniKey(22 characters) error = %v", err)
+	}
+}
+
+func TestParseMiniKeyInvalid(t *testing.T) {
+	tests := []struct {
+		key string
+		err error
+	}{
+		{"", ErrMiniKeyFormat},
+		{"S6c56bnXQiBjk9mqSYE7yk", ErrMiniKeyCheck},
+		{"S6c56bnXQiBjk9mqSYE7ykVQ7NzrR", ErrMiniKeyFormat}, // 29 characters
+		{"T6c56bnXQiBjk9mqSYE7ykVQ7NzrRy", ErrMiniKeyFormat},
+		{"S6c56bnXQiBjk9mqSYE7ykVQ7NzrR0", ErrMiniKeyFormat}, // '0' is not Base58
+		{"S6c56bnXQiBjk9mqSYE7ykVQ7NzrRz", ErrMiniKeyCheck},
+	}
+	for _, tt := range tests {
+		if _, err := ParseMiniKey(tt.key); err != tt.err {
+			t.Errorf("ParseMiniKey(%q) error = %v, want %v", tt.key, err, tt.err)
+		}
+	}
+}
+
+func TestGenerateMiniKey(t *testing.T) {
+	for _, length := range []int{22, 30} {
+		key, k, err := GenerateMiniKey(length, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(key) != length || key[0] != 'S' {
+			t.Errorf("GenerateMiniKey(%d) = %s", length, key)
+		}
+		h := sha256.Sum256([]byte(key))
+		if !bytes.Equal(k.Bytes(), h[:]) {
+			t.Errorf("GenerateMiniKey(%d) key = %s, want SHA256(%s)", length, k.Hex(), key)
+		}
+		if _, err := ParseMiniKey(key); err != nil {
+			t.Errorf("ParseMiniKey(%s) error = %v", key, err)
+		}
+	}
+	if _, _, err := GenerateMiniKey(21, nil); err != ErrMiniKeyFormat {
+		t.Errorf("GenerateMiniKey(21) error = %v, want %v", err, ErrMiniKeyFormat)
+	}
+}
@@ -0,0 +1,133 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// Multisig scripts and their P2SH, P2WSH and P2SH-P2WSH addresses.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0011.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0067.mediawiki
+//	https://github.com/bitcoin/bips/blob/master/bip-0141.mediawiki#p2wsh
+
+// MultisigError is returned for an m-of-n combination that cannot be
+// expressed as a standard OP_CHECKMULTISIG script.
+type MultisigError struct {
+	M, N int
+}
+
+func (e MultisigError) Error() string {
+	return fmt.Sprintf("invalid %d-of-%d multisig, want 1 <= m <= n <= 16", e.M, e.N)
+}
+
+// MultisigScript builds the redeem (or witness) script
+//
+//	OP_m <pubkey 1> ... <pubkey n> OP_n OP_CHECKMULTISIG
+//
+// from compressed or uncompressed public keys.  With sortKeys the keys are
+// ordered lexicographically as BIP67 specifies; otherwise they are used in
+// the given order.
+//
+// The script itself is not checked against a wrapping: P2SHAddress rejects
+// it above 520 bytes (e.g. 16 compressed keys), and the witness addresses
+// reject uncompressed keys.
+func MultisigScript(m int, pubKeys [][]byte, sortKeys bool) ([]byte, error) {
+	n := len(pubKeys)
+	if m < 1 || m > n || n > 16 {
+		return nil, MultisigError{m, n}
+	}
+	keys := make([][]byte, n)
+	for i, key := range pubKeys {
+		if len(key) != 33 && len(key) != 65 {
+			return nil, fmt.Errorf("multisig public key %d: %w", i, errInvalidPubKeyLen)
+		}
+		if _, err := ParsePubKey(key); err != nil {
+			return nil, fmt.Errorf("multisig public key %d: %w", i, err)
+		}
+		keys[i] = key
+	}
+	if sortKeys {
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i], keys[j]) < 0
+		})
+	}
+
+	script := []byte{byte(OP_1 + m - 1)}
+	for _, key := range keys {
+		script = append(script, byte(len(key)))
+		script = append(script, key...)
+	}
+	return append(script, byte(OP_1+n-1), OP_CHECKMULTISIG), nil
+}
+
+// checkWitnessScript applies the witness rules to a witness script: the
+// policy size limit, and BIP143 compressed keys when it is a multisig
+// script.
+func checkWitnessScript(witnessScript []byte) error {
+	if len(witnessScript) > MaxP2WSHScriptSize {
+		return ScriptSizeError{"witness script", len(witnessScript), MaxP2WSHScriptSize}
+	}
+	for _, key := range multisigKeys(witnessScript) {
+		if len(key) != 33 {
+			return ErrUncompressedPubKey
+		}
+	}
+	return nil
+}
+
+// multisigKeys returns the public keys of a script built by
+// MultisigScript, or nil for any other script.
+func multisigKeys(script []byte) [][]byte {
+	n := len(script)
+	if n < 3 || script[0] < OP_1 || script[0] > OP_16 ||
+		script[n-2] < OP_1 || script[n-2] > OP_16 || script[n-1] != OP_CHECKMULTISIG {
+		return nil
+	}
+	var keys [][]byte
+	for i := 1; i < n-2; {
+		l := int(script[i])
+		if l != 33 && l != 65 || i+1+l > n-2 {
+			return nil
+		}
+		keys = append(keys, script[i+1:i+1+l])
+		i += 1 + l
+	}
+	if len(keys) != int(script[n-2]-OP_1+1) {
+		return nil
+	}
+	return keys
+}
+
+// P2WSHAddress returns the native SegWit (witness v0 script hash) address
+// of witnessScript: bc1q... with SHA256(witnessScript) as the program.
+func P2WSHAddress(witnessScript []byte, net *Network) (string, error) {
+	hrp, err := segwitHRP(net)
+	if err != nil {
+		return "", err
+	}
+	if err := checkWitnessScript(witnessScript); err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(witnessScript)
+	return EncodeSegWitAddress(hrp, 0, h[:])
+}
+
+// P2SHP2WSHAddress returns the nested SegWit address of witnessScript:
+// the P2WSH program OP_0 <SHA256(witnessScript)> wrapped as a P2SH redeem
+// script and encoded with the network's script-hash prefix.  The redeem
+// script is the 34-byte program, so the witness script is only bound by
+// the witness limits.  On a chain without SegWit that redeem script is
+// anyone-can-spend, so it fails with ErrNoSegWit there.
+func P2SHP2WSHAddress(witnessScript []byte, net *Network) (string, error) {
+	if _, err := segwitHRP(net); err != nil {
+		return "", err
+	}
+	if err := checkWitnessScript(witnessScript); err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(witnessScript)
+	return P2SHAddress(P2WSHScript(h[:]), net)
+}
@@ -0,0 +1,143 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// testPubKeys returns the public keys of the private keys 1 to n.
+func testPubKeys(n int, compressed bool) [][]byte {
+	keys := make([][]byte, n)
+	for i := range keys {
+		P := ECBaseMul(big.NewInt(int64(i + 1)))
+		if compressed {
+			keys[i] = P.Serialize()
+		} else {
+			keys[i] = P.SerializeUncompressed()
+		}
+	}
+	return keys
+}
+
+func TestMultisigScriptBIP67(t *testing.T) {
+	tests := []struct {
+		m       int
+		pubKeys []string // in the unsorted input order
+		script  string
+		address string
+	}{
+		{
+			2,
+			[]string{
+				"02ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f8",
+				"02fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f",
+			},
+			"522102fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f" +
+				"2102ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f852ae",
+			"39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z",
+		},
+		{
+			2,
+			[]string{
+				"02632b12f4ac5b1d1b72b2a3b508c19172de44f6f46bcee50ba33f3f9291e47ed0",
+				"027735a29bae7780a9755fae7a1c4374c656ac6a69ea9f3697fda61bb99a4f3e77",
+				"02e2cc6bd5f45edd43bebe7cb9b675f0ce9ed3efe613b177588290ad188d11b404",
+			},
+			"522102632b12f4ac5b1d1b72b2a3b508c19172de44f6f46bcee50ba33f3f9291e47ed0" +
+				"21027735a29bae7780a9755fae7a1c4374c656ac6a69ea9f3697fda61bb99a4f3e77" +
+				"2102e2cc6bd5f45edd43bebe7cb9b675f0ce9ed3efe613b177588290ad188d11b40453ae",
+			"3CKHTjBKxCARLzwABMu9yD85kvtm7WnMfH",
+		},
+	}
+	for _, tt := range tests {
+		var keys [][]byte
+		for _, k := range tt.pubKeys {
+			keys = append(keys, mustHex(t, k))
+		}
+		script, err := MultisigScript(tt.m, keys, true)
+		if err != nil {
+			t.Fatalf("MultisigScript: %v", err)
+		}
+		if got := hex.EncodeToString(script); got != tt.script {
+			t.Errorf("MultisigScript = %s, want %s", got, tt.script)
+		}
+		if got, err := P2SHAddress(script, BTCMainnet); err != nil || got != tt.address {
+			t.Errorf("P2SHAddress = %q, %v, want %q", got, err, tt.address)
+		}
+	}
+}
+
+func TestMultisigScriptInvalid(t *testing.T) {
+	keys := testPubKeys(17, true)
+	tests := []struct {
+		m, n int
+	}{
+		{0, 3},
+		{4, 3},
+		{1, 0},
+		{1, 17},
+	}
+	for _, tt := range tests {
+		_, err := MultisigScript(tt.m, keys[:tt.n], false)
+		if err != (MultisigError{tt.m, tt.n}) {
+			t.Errorf("MultisigScript(%d-of-%d) error = %v, want %v", tt.m, tt.n, err, MultisigError{tt.m, tt.n})
+		}
+	}
+	if _, err := MultisigScript(1, [][]byte{keys[0][:32]}, false); !errors.Is(err, errInvalidPubKeyLen) {
+		t.Errorf("MultisigScript(short key) error = %v, want %v", err, errInvalidPubKeyLen)
+	}
+}
+
+func TestMultisigAddressLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		pubKeys    [][]byte
+		p2sh       error
+		p2wsh      error
+		p2shP2wsh  error
+		scriptSize int
+	}{
+		{"15 compressed", testPubKeys(15, true), nil, nil, nil, 513},
+		{"16 compressed", testPubKeys(16, true),
+			ScriptSizeError{"redeem script", 547, MaxScriptElementSize}, nil, nil, 547},
+		{"3 uncompressed", testPubKeys(3, false),
+			nil, ErrUncompressedPubKey, ErrUncompressedPubKey, 201},
+		{"8 uncompressed", testPubKeys(8, false),
+			ScriptSizeError{"redeem script", 531, MaxScriptElementSize}, ErrUncompressedPubKey, ErrUncompressedPubKey, 531},
+	}
+	for _, tt := range tests {
+		script, err := MultisigScript(1, tt.pubKeys, true)
+		if err != nil {
+			t.Fatalf("%s: MultisigScript: %v", tt.name, err)
+		}
+		if len(script) != tt.scriptSize {
+			t.Errorf("%s: script size = %d, want %d", tt.name, len(script), tt.scriptSize)
+		}
+		if _, err := P2SHAddress(script, BTCMainnet); err != tt.p2sh {
+			t.Errorf("%s: P2SHAddress error = %v, want %v", tt.name, err, tt.p2sh)
+		}
+		if _, err := P2WSHAddress(script, BTCMainnet); err != tt.p2wsh {
+			t.Errorf("%s: P2WSHAddress error = %v, want %v", tt.name, err, tt.p2wsh)
+		}
+		if _, err := P2SHP2WSHAddress(script, BTCMainnet); err != tt.p2shP2wsh {
+			t.Errorf("%s: P2SHP2WSHAddress error = %v, want %v", tt.name, err, tt.p2shP2wsh)
+		}
+	}
+
+	long := make([]byte, MaxP2WSHScriptSize+1)
+	want := ScriptSizeError{"witness script", len(long), MaxP2WSHScriptSize}
+	if _, err := P2WSHAddress(long, BTCMainnet); err != want {
+		t.Errorf("P2WSHAddress(%d bytes) error = %v, want %v", len(long), err, want)
+	}
+	script, _ := MultisigScript(2, testPubKeys(3, true), true)
+	for _, net := range []*Network{KMD, DOGE} {
+		if _, err := P2WSHAddress(script, net); !errors.Is(err, ErrNoSegWit) {
+			t.Errorf("P2WSHAddress(%s) error = %v, want %v", net, err, ErrNoSegWit)
+		}
+		if _, err := P2SHP2WSHAddress(script, net); !errors.Is(err, ErrNoSegWit) {
+			t.Errorf("P2SHP2WSHAddress(%s) error = %v, want %v", net, err, ErrNoSegWit)
+		}
+	}
+}
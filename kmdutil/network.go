@@ -0,0 +1,276 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Network holds the address and key prefixes of a chain, so that the
+// encoders do not need hard-coded version bytes.  See:
+//
+//	https://en.bitcoin.it/wiki/List_of_address_prefixes
+//	https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+type Network struct {
+	// Name is the registry key, e.g. "btc", "btc-testnet" or "kmd".
+	Name string
+
+	// PubKeyHashAddrID and ScriptHashAddrID are the Base58Check version
+	// prefixes of P2PKH and P2SH addresses.  Zcash-family chains use two
+	// bytes.
+	PubKeyHashAddrID []byte
+	ScriptHashAddrID []byte
+
+	// PrivateKeyID is the WIF version byte.
+	PrivateKeyID byte
+
+	// Bech32HRP is the human-readable part of SegWit addresses, or empty
+	// when the chain has no SegWit.
+	Bech32HRP string
+
+	// CashAddrPrefix is the CashAddr prefix, e.g. "bitcoincash", or empty
+	// when the chain does not use CashAddr.
+	CashAddrPrefix string
+
+	// HDPrivateKeyID and HDPublicKeyID are the BIP32 extended key
+	// version bytes (xprv/xpub).
+	HDPrivateKeyID [4]byte
+	HDPublicKeyID  [4]byte
+
+	// HDCoinType is the SLIP-44 coin type of BIP44-style paths, e.g. 0
+	// for Bitcoin, 1 for all testnets and 141 for Komodo.
+	HDCoinType uint32
+
+	// MessageMagic is the prefix hashed into signed messages.
+	MessageMagic string
+
+	// NetMagic is the P2P message start read as a little-endian uint32,
+	// e.g. 0xd9b4bef9 for the bytes f9 be b4 d9.
+	NetMagic uint32
+
+	// P2PPort and RPCPort are the default ports of the node.
+	P2PPort uint16
+	RPCPort uint16
+}
+
+func (net *Network) String() string {
+	return net.Name
+}
+
+// SupportsSegWit reports whether the chain has SegWit addresses, and so
+// SLIP-132 versions for its extended keys.
+func (net *Network) SupportsSegWit() bool {
+	return net.Bech32HRP != ""
+}
+
+var (
+	bip32Private = [4]byte{0x04, 0x88, 0xad, 0xe4} // xprv
+	bip32Public  = [4]byte{0x04, 0x88, 0xb2, 0x1e} // xpub
+	tbip32Priv   = [4]byte{0x04, 0x35, 0x83, 0x94} // tprv
+	tbip32Pub    = [4]byte{0x04, 0x35, 0x87, 0xcf} // tpub
+)
+
+// Built-in networks.
+var (
+	BTCMainnet = &Network{
+		Name:             "btc",
+		PubKeyHashAddrID: []byte{0x00},
+		ScriptHashAddrID: []byte{0x05},
+		PrivateKeyID:     0x80,
+		Bech32HRP:        "bc",
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       0,
+		MessageMagic:     "Bitcoin Signed Message:\n",
+		NetMagic:         0xd9b4bef9,
+		P2PPort:          8333,
+		RPCPort:          8332,
+	}
+	BTCTestnet = &Network{
+		Name:             "btc-testnet",
+		PubKeyHashAddrID: []byte{0x6f},
+		ScriptHashAddrID: []byte{0xc4},
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "tb",
+		HDPrivateKeyID:   tbip32Priv,
+		HDPublicKeyID:    tbip32Pub,
+		HDCoinType:       1,
+		MessageMagic:     "Bitcoin Signed Message:\n",
+		NetMagic:         0x0709110b,
+		P2PPort:          18333,
+		RPCPort:          18332,
+	}
+	BTCSignet = &Network{
+		Name:             "btc-signet",
+		PubKeyHashAddrID: []byte{0x6f},
+		ScriptHashAddrID: []byte{0xc4},
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "tb",
+		HDPrivateKeyID:   tbip32Priv,
+		HDPublicKeyID:    tbip32Pub,
+		HDCoinType:       1,
+		MessageMagic:     "Bitcoin Signed Message:\n",
+		NetMagic:         0x40cf030a,
+		P2PPort:          38333,
+		RPCPort:          38332,
+	}
+	BTCRegtest = &Network{
+		Name:             "btc-regtest",
+		PubKeyHashAddrID: []byte{0x6f},
+		ScriptHashAddrID: []byte{0xc4},
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "bcrt",
+		HDPrivateKeyID:   tbip32Priv,
+		HDPublicKeyID:    tbip32Pub,
+		HDCoinType:       1,
+		MessageMagic:     "Bitcoin Signed Message:\n",
+		NetMagic:         0xdab5bffa,
+		P2PPort:          18444,
+		RPCPort:          18443,
+	}
+	KMD = &Network{
+		Name:             "kmd",
+		PubKeyHashAddrID: []byte{0x3c},
+		ScriptHashAddrID: []byte{0x55},
+		PrivateKeyID:     0xbc,
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       141,
+		MessageMagic:     "Komodo Signed Message:\n",
+		NetMagic:         0x8de4eef9,
+		P2PPort:          7770,
+		RPCPort:          7771,
+	}
+	LTC = &Network{
+		Name:             "ltc",
+		PubKeyHashAddrID: []byte{0x30},
+		ScriptHashAddrID: []byte{0x32},
+		PrivateKeyID:     0xb0,
+		Bech32HRP:        "ltc",
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       2,
+		MessageMagic:     "Litecoin Signed Message:\n",
+		NetMagic:         0xdbb6c0fb,
+		P2PPort:          9333,
+		RPCPort:          9332,
+	}
+	DOGE = &Network{
+		Name:             "doge",
+		PubKeyHashAddrID: []byte{0x1e},
+		ScriptHashAddrID: []byte{0x16},
+		PrivateKeyID:     0x9e,
+		HDPrivateKeyID:   [4]byte{0x02, 0xfa, 0xc3, 0x98}, // dgpv
+		HDPublicKeyID:    [4]byte{0x02, 0xfa, 0xca, 0xfd}, // dgub
+		HDCoinType:       3,
+		MessageMagic:     "Dogecoin Signed Message:\n",
+		NetMagic:         0xc0c0c0c0,
+		P2PPort:          22556,
+		RPCPort:          22555,
+	}
+	DASH = &Network{
+		Name:             "dash",
+		PubKeyHashAddrID: []byte{0x4c},
+		ScriptHashAddrID: []byte{0x10},
+		PrivateKeyID:     0xcc,
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       5,
+		MessageMagic:     "DarkCoin Signed Message:\n",
+		NetMagic:         0xbd6b0cbf,
+		P2PPort:          9999,
+		RPCPort:          9998,
+	}
+	ZEC = &Network{
+		Name:             "zec",
+		PubKeyHashAddrID: []byte{0x1c, 0xb8}, // t1...
+		ScriptHashAddrID: []byte{0x1c, 0xbd}, // t3...
+		PrivateKeyID:     0x80,
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       133,
+		MessageMagic:     "Zcash Signed Message:\n",
+		NetMagic:         0x6427e924,
+		P2PPort:          8233,
+		RPCPort:          8232,
+	}
+	ZEN = &Network{
+		Name:             "zen",
+		PubKeyHashAddrID: []byte{0x20, 0x89}, // zn...
+		ScriptHashAddrID: []byte{0x20, 0x96}, // zs...
+		PrivateKeyID:     0x80,
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       121,
+		MessageMagic:     "Zcash Signed Message:\n",
+		NetMagic:         0x68736163,
+		P2PPort:          9033,
+		RPCPort:          8231,
+	}
+	BCH = &Network{
+		Name:             "bch",
+		PubKeyHashAddrID: []byte{0x00},
+		ScriptHashAddrID: []byte{0x05},
+		PrivateKeyID:     0x80,
+		CashAddrPrefix:   "bitcoincash",
+		HDPrivateKeyID:   bip32Private,
+		HDPublicKeyID:    bip32Public,
+		HDCoinType:       145,
+		MessageMagic:     "Bitcoin Signed Message:\n",
+		NetMagic:         0xe8f3e1e3,
+		P2PPort:          8333,
+		RPCPort:          8332,
+	}
+)
+
+var (
+	// ErrDuplicateNetwork is returned when registering a name twice.
+	ErrDuplicateNetwork = errors.New("duplicate network name")
+
+	// ErrUnknownNetwork is returned by NetworkByName for a name that was
+	// never registered.
+	ErrUnknownNetwork = errors.New("unknown network")
+
+	// ErrNoSegWit is returned when a SegWit address is requested for a
+	// network without a Bech32 HRP.
+	ErrNoSegWit = errors.New("network does not support segwit")
+)
+
+var (
+	registeredNets = map[string]*Network{}
+	netOrder       []*Network
+)
+
+func init() {
+	for _, net := range []*Network{
+		BTCMainnet, BTCTestnet, BTCSignet, BTCRegtest,
+		KMD, LTC, DOGE, DASH, ZEC, ZEN, BCH,
+	} {
+		if err := RegisterNetwork(net); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterNetwork adds net to the registry under net.Name.
+func RegisterNetwork(net *Network) error {
+	if _, ok := registeredNets[net.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateNetwork, net.Name)
+	}
+	registeredNets[net.Name] = net
+	netOrder = append(netOrder, net)
+	return nil
+}
+
+// NetworkByName returns the registered network called name.
+func NetworkByName(name string) (*Network, error) {
+	net, ok := registeredNets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, name)
+	}
+	return net, nil
+}
+
+// Networks returns every registered network in registration order.
+func Networks() []*Network {
+	return append([]*Network(nil), netOrder...)
+}
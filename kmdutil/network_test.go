@@ -0,0 +1,55 @@
+package kmdutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNetworkByName(t *testing.T) {
+	for _, want := range []*Network{BTCMainnet, BTCTestnet, KMD, LTC, DOGE, ZEC, BCH} {
+		got, err := NetworkByName(want.Name)
+		if err != nil || got != want {
+			t.Errorf("NetworkByName(%q) = %v, %v, want %v", want.Name, got, err, want)
+		}
+	}
+	if _, err := NetworkByName("nosuchcoin"); !errors.Is(err, ErrUnknownNetwork) {
+		t.Errorf("NetworkByName(nosuchcoin) error = %v, want %v", err, ErrUnknownNetwork)
+	}
+}
+
+func TestRegisterNetwork(t *testing.T) {
+	if err := RegisterNetwork(&Network{Name: "kmd"}); !errors.Is(err, ErrDuplicateNetwork) {
+		t.Errorf("RegisterNetwork(kmd) error = %v, want %v", err, ErrDuplicateNetwork)
+	}
+	if got, _ := NetworkByName("kmd"); got != KMD {
+		t.Errorf("NetworkByName(kmd) = %p after a failed registration, want %p", got, KMD)
+	}
+
+	nets := Networks()
+	if nets[0] != BTCMainnet {
+		t.Errorf("Networks()[0] = %v, want %v", nets[0], BTCMainnet)
+	}
+	nets[0] = nil
+	if Networks()[0] != BTCMainnet {
+		t.Error("Networks returned the registry slice itself")
+	}
+}
+
+func TestP2PKHAddressNetworks(t *testing.T) {
+	// Compressed public key of private key 1.
+	pubKey := CurveG().Serialize()
+	tests := []struct {
+		net  *Network
+		want string
+	}{
+		{BTCMainnet, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{BTCTestnet, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{LTC, "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ"},
+		{DOGE, "DFpN6QqFfUm3gKNaxN6tNcab1FArL9cZLE"},
+	}
+	for _, tt := range tests {
+		if got := P2PKHAddress(pubKey, tt.net); got != tt.want {
+			t.Errorf("P2PKHAddress(%s) = %s, want %s", tt.net, got, tt.want)
+		}
+	}
+}
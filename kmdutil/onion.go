@@ -0,0 +1,76 @@
+package kmdutil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base32"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Tor v3 onion service addresses.  See:
+//
+//	https://gitweb.torproject.org/torspec.git/tree/rend-spec-v3.txt - Section 6.
+//
+//	onion_address = base32(pubkey || checksum || version) + ".onion"
+//	checksum = H(".onion checksum" || pubkey || version)[:2]
+
+const onionVersion = 0x03
+
+var (
+	// ErrOnionFormat indicates a string that is not 56 base32 characters
+	// followed by ".onion".
+	ErrOnionFormat = errors.New("invalid onion v3 address format")
+
+	// ErrOnionVersion indicates an onion address version other than 3.
+	ErrOnionVersion = errors.New("unsupported onion address version")
+
+	// ErrOnionChecksum indicates that the two checksum bytes of an onion
+	// address do not match its public key.
+	ErrOnionChecksum = errors.New("onion address checksum error")
+)
+
+func onionChecksum(pubKey []byte, version byte) []byte {
+	h := sha3.New256()
+	h.Write([]byte(".onion checksum"))
+	h.Write(pubKey)
+	h.Write([]byte{version})
+	return h.Sum(nil)[:2]
+}
+
+// OnionV3Address returns the lower case v3 onion address of an ed25519
+// public key.
+func OnionV3Address(pubKey ed25519.PublicKey) string {
+	b := make([]byte, 0, ed25519.PublicKeySize+3)
+	b = append(b, pubKey...)
+	b = append(b, onionChecksum(pubKey, onionVersion)...)
+	b = append(b, onionVersion)
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)) + ".onion"
+}
+
+// DecodeOnionV3Address returns the ed25519 public key of a v3 onion
+// address after checking its version and checksum.
+func DecodeOnionV3Address(addr string) (ed25519.PublicKey, error) {
+	s := strings.ToUpper(addr)
+	if !strings.HasSuffix(s, ".ONION") {
+		return nil, ErrOnionFormat
+	}
+	s = strings.TrimSuffix(s, ".ONION")
+	if len(s) != 56 {
+		return nil, ErrOnionFormat
+	}
+	b, err := base32.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrOnionFormat
+	}
+	pubKey, sum, version := b[:32], b[32:34], b[34]
+	if version != onionVersion {
+		return nil, ErrOnionVersion
+	}
+	if !bytes.Equal(sum, onionChecksum(pubKey, version)) {
+		return nil, ErrOnionChecksum
+	}
+	return ed25519.PublicKey(pubKey), nil
+}
@@ -0,0 +1,44 @@
+package kmdutil
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+)
+
+func TestOnionV3Address(t *testing.T) {
+	const addr = "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion"
+	pubKey, err := DecodeOnionV3Address(addr)
+	if err != nil {
+		t.Fatalf("DecodeOnionV3Address(%s): %v", addr, err)
+	}
+	if got := OnionV3Address(pubKey); got != addr {
+		t.Errorf("OnionV3Address = %s, want %s", got, addr)
+	}
+	if _, err := DecodeOnionV3Address(strings.ToUpper(addr)); err != nil {
+		t.Errorf("DecodeOnionV3Address(upper case): %v", err)
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	a, err := ParseAddress(OnionV3Address(pub))
+	if err != nil || a.Type != AddrOnionV3 || string(a.Payload) != string(pub) {
+		t.Errorf("ParseAddress(onion) = %+v, %v", a, err)
+	}
+}
+
+func TestOnionV3AddressInvalid(t *testing.T) {
+	tests := []struct {
+		addr string
+		want error
+	}{
+		{"duckduckgogh42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion", ErrOnionChecksum},
+		{"duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzcza.onion", ErrOnionFormat},
+		{"duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad", ErrOnionFormat},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeOnionV3Address(tt.addr); err != tt.want {
+			t.Errorf("DecodeOnionV3Address(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+}
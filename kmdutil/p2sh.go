@@ -0,0 +1,30 @@
+package kmdutil
+
+// P2SHAddress returns the Base58Check pay-to-script-hash address of
+// redeemScript with the network's script-hash prefix, e.g. 0x05 for
+// Bitcoin (3...) and 0x55 for Komodo (b...).  A redeem script above
+// MaxScriptElementSize could never be spent, so it is an error.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0016.mediawiki
+func P2SHAddress(redeemScript []byte, net *Network) (string, error) {
+	if len(redeemScript) > MaxScriptElementSize {
+		return "", ScriptSizeError{"redeem script", len(redeemScript), MaxScriptElementSize}
+	}
+	return Base58CheckEncodePrefix(Hash160(redeemScript), net.ScriptHashAddrID), nil
+}
+
+// P2SHP2WPKHAddress returns the nested SegWit address of a compressed
+// public key: the P2WPKH program OP_0 <Hash160(pubKey)> wrapped as a
+// P2SH redeem script.  On a chain without SegWit that redeem script is
+// anyone-can-spend, so it fails with ErrNoSegWit there.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0141.mediawiki#p2wpkh-nested-in-bip16-p2sh
+func P2SHP2WPKHAddress(pubKey []byte, net *Network) (string, error) {
+	if _, err := segwitHRP(net); err != nil {
+		return "", err
+	}
+	if err := checkCompressedPubKey(pubKey); err != nil {
+		return "", err
+	}
+	return P2SHAddress(P2WPKHScript(Hash160(pubKey)), net)
+}
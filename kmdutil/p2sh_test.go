@@ -0,0 +1,51 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestP2SHP2WPKHAddress(t *testing.T) {
+	tests := []struct {
+		pubKey string
+		net    *Network
+		want   string
+		err    error
+	}{
+		// Private key 1.
+		{"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			BTCMainnet, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", nil},
+		// BIP49 test vector, m/49'/1'/0'/0/0.
+		{"03a1af804ac108a8a51782198c2d034b28bf90c8803f5a53f76276fa69a4eae77f",
+			BTCTestnet, "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2", nil},
+		// The redeem script would be anyone-can-spend without SegWit.
+		{"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			KMD, "", ErrNoSegWit},
+	}
+	for _, tt := range tests {
+		got, err := P2SHP2WPKHAddress(mustHex(t, tt.pubKey), tt.net)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("P2SHP2WPKHAddress(%s, %s) = %q, %v, want %q, %v", tt.pubKey, tt.net, got, err, tt.want, tt.err)
+		}
+	}
+	if _, err := P2SHP2WPKHAddress(CurveG().SerializeUncompressed(), BTCMainnet); err != ErrUncompressedPubKey {
+		t.Errorf("P2SHP2WPKHAddress(uncompressed) error = %v, want %v", err, ErrUncompressedPubKey)
+	}
+}
+
+func TestP2WPKHScript(t *testing.T) {
+	h := mustHex(t, "751e76e8199196d454941c45d1b3a323f1433bd6")
+	tests := []struct {
+		name, got, want string
+	}{
+		{"P2PKHScript", hex.EncodeToString(P2PKHScript(h)), "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{"P2SHScript", hex.EncodeToString(P2SHScript(h)), "a914751e76e8199196d454941c45d1b3a323f1433bd687"},
+		{"P2WPKHScript", hex.EncodeToString(P2WPKHScript(h)), "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
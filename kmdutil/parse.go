@@ -0,0 +1,204 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddressType is the output type an address pays to.
+type AddressType int
+
+const (
+	AddrP2PKH AddressType = iota + 1
+	AddrP2SH
+	AddrP2WPKH
+	AddrP2WSH
+	AddrP2TR
+	// AddrWitnessUnknown is a valid witness output of a version or
+	// program size with no defined spending rules yet.
+	AddrWitnessUnknown
+	AddrOnionV3
+)
+
+func (t AddressType) String() string {
+	switch t {
+	case AddrP2PKH:
+		return "p2pkh"
+	case AddrP2SH:
+		return "p2sh"
+	case AddrP2WPKH:
+		return "p2wpkh"
+	case AddrP2WSH:
+		return "p2wsh"
+	case AddrP2TR:
+		return "p2tr"
+	case AddrWitnessUnknown:
+		return "witness_unknown"
+	case AddrOnionV3:
+		return "onion_v3"
+	}
+	return fmt.Sprintf("AddressType(%d)", int(t))
+}
+
+// Address encodings reported by ParseAddress.
+const (
+	EncodingBase58Check = "base58check"
+	EncodingBech32      = "bech32"
+	EncodingBech32m     = "bech32m"
+	EncodingCashAddr    = "cashaddr"
+	EncodingOnion       = "base32"
+)
+
+// ErrUnknownHRP is returned for a valid Bech32 string whose HRP does not
+// belong to any registered network.
+var ErrUnknownHRP = errors.New("unknown bech32 human-readable part")
+
+// Address is a parsed address.
+type Address struct {
+	Encoding string
+	// Network is nil for onion addresses.
+	Network *Network
+	Type    AddressType
+	// WitnessVersion is only meaningful for SegWit addresses.
+	WitnessVersion byte
+	// Payload is the Hash160 of Base58Check addresses, the witness
+	// program of SegWit addresses and the ed25519 key of onion addresses.
+	Payload []byte
+	// ScriptPubKey is the output script paying to the address, or nil
+	// for onion addresses.
+	ScriptPubKey []byte
+}
+
+// ParseAddress works out the encoding, network and type of an address.
+// CashAddr addresses must include their prefix.  Failures are reported
+// with the decoder's own errors, e.g. ErrChecksum, ErrBech32Checksum,
+// ErrCashAddrChecksum, ErrOnionChecksum, ErrUnknownAddressPrefix or
+// ErrUnknownHRP.  A mistyped Bech32 address gets the Bech32 error, not a
+// Base58 one.
+func ParseAddress(s string) (*Address, error) {
+	if strings.HasSuffix(strings.ToLower(s), ".onion") {
+		pubKey, err := DecodeOnionV3Address(s)
+		if err != nil {
+			return nil, err
+		}
+		return &Address{
+			Encoding: EncodingOnion,
+			Type:     AddrOnionV3,
+			Payload:  pubKey,
+		}, nil
+	}
+
+	// CashAddr needs its prefix here, since a bare q... payload could
+	// equally be a Base58 string.
+	if sep := strings.LastIndexByte(s, ':'); sep > 0 {
+		prefix := strings.ToLower(s[:sep])
+		for _, net := range Networks() {
+			if net.CashAddrPrefix != "" && net.CashAddrPrefix == prefix {
+				return parseCashAddr(s, net)
+			}
+		}
+		return nil, fmt.Errorf("%w: cashaddr prefix %s", ErrUnknownAddressPrefix, prefix)
+	}
+
+	// A Base58 string can contain '1' too, so only treat s as Bech32 when
+	// the part before the last '1' is a known HRP or the checksum holds.
+	// Bech32 is single case while Base58 addresses almost never are, so a
+	// single case string that is not valid Base58 either reports the
+	// Bech32 error rather than a misleading Base58 one.
+	if sep := strings.LastIndexByte(s, '1'); sep > 0 {
+		hrp := strings.ToLower(s[:sep])
+		if net := networkByHRP(hrp); net != nil {
+			return parseSegWitAddress(s, net)
+		}
+		_, _, _, bech32Err := Bech32Decode(s)
+		if bech32Err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownHRP, hrp)
+		}
+		if s == strings.ToLower(s) || s == strings.ToUpper(s) {
+			if addr, err := parseBase58Address(s); err == nil {
+				return addr, nil
+			}
+			return nil, bech32Err
+		}
+	}
+	return parseBase58Address(s)
+}
+
+func parseBase58Address(s string) (*Address, error) {
+	net, scriptHash, hash, err := DecodeBase58Address(s)
+	if err != nil {
+		return nil, err
+	}
+	addr := &Address{
+		Encoding:     EncodingBase58Check,
+		Network:      net,
+		Type:         AddrP2PKH,
+		Payload:      hash,
+		ScriptPubKey: P2PKHScript(hash),
+	}
+	if scriptHash {
+		addr.Type = AddrP2SH
+		addr.ScriptPubKey = P2SHScript(hash)
+	}
+	return addr, nil
+}
+
+// networkByHRP returns the first registered network using hrp.
+func networkByHRP(hrp string) *Network {
+	for _, net := range Networks() {
+		if net.Bech32HRP != "" && net.Bech32HRP == hrp {
+			return net
+		}
+	}
+	return nil
+}
+
+func parseSegWitAddress(s string, net *Network) (*Address, error) {
+	_, version, program, err := DecodeSegWitAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	addr := &Address{
+		Encoding:       EncodingBech32m,
+		Network:        net,
+		Type:           AddrWitnessUnknown,
+		WitnessVersion: version,
+		Payload:        program,
+		ScriptPubKey:   WitnessScript(version, program),
+	}
+	switch {
+	case version == 0:
+		addr.Encoding = EncodingBech32
+		addr.Type = AddrP2WPKH
+		if len(program) == 32 {
+			addr.Type = AddrP2WSH
+		}
+	case version == 1 && len(program) == 32:
+		addr.Type = AddrP2TR
+	}
+	return addr, nil
+}
+
+func parseCashAddr(s string, net *Network) (*Address, error) {
+	_, scriptHash, hash, err := DecodeCashAddr(s, "")
+	if err != nil {
+		return nil, err
+	}
+	addr := &Address{
+		Encoding: EncodingCashAddr,
+		Network:  net,
+		Type:     AddrP2PKH,
+		Payload:  hash,
+	}
+	if scriptHash {
+		addr.Type = AddrP2SH
+	}
+	if len(hash) == 20 {
+		addr.ScriptPubKey = P2PKHScript(hash)
+		if scriptHash {
+			addr.ScriptPubKey = P2SHScript(hash)
+		}
+	}
+	return addr, nil
+}
@@ -0,0 +1,74 @@
+package kmdutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		encoding string
+		net      *Network
+		typ      AddressType
+		script   string
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", EncodingBase58Check, BTCMainnet, AddrP2PKH,
+			"76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", EncodingBase58Check, BTCMainnet, AddrP2SH,
+			"a914b472a266d0bd89c13706a4132ccfb16f7c3b9fcb87"},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", EncodingBech32, BTCMainnet, AddrP2WPKH,
+			"0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", EncodingBech32, BTCMainnet, AddrP2WPKH,
+			"0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", EncodingBech32, BTCTestnet, AddrP2WPKH,
+			"0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", EncodingBech32, BTCMainnet, AddrP2WSH,
+			"00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", EncodingBech32m, BTCMainnet, AddrP2TR,
+			"512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		{"BC1SW50QGDZ25J", EncodingBech32m, BTCMainnet, AddrWitnessUnknown, "6002751e"},
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", EncodingCashAddr, BCH, AddrP2PKH,
+			"76a91476a04053bda0a88bda5177b86a15c3b29f55987388ac"},
+	}
+	for _, tt := range tests {
+		a, err := ParseAddress(tt.addr)
+		if err != nil {
+			t.Errorf("ParseAddress(%s): %v", tt.addr, err)
+			continue
+		}
+		if a.Encoding != tt.encoding || a.Network != tt.net || a.Type != tt.typ ||
+			!bytes.Equal(a.ScriptPubKey, mustHex(t, tt.script)) {
+			t.Errorf("ParseAddress(%s) = %s %v %v %x, want %s %v %v %s", tt.addr,
+				a.Encoding, a.Network, a.Type, a.ScriptPubKey, tt.encoding, tt.net, tt.typ, tt.script)
+		}
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	unknownHRP, err := Bech32Encode("xyz", []byte{0, 14, 20, 15, 7, 13, 26, 0, 25, 18, 6, 11, 13, 8, 21}, Bech32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr string
+		want error
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", ErrChecksum},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", ErrBech32Checksum},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3T4", ErrBech32MixedCase},
+		// A valid Bech32 string of no registered network.
+		{unknownHRP, ErrUnknownHRP},
+		// A typo in the HRP must not be reported as a Base58 error.
+		{"bx1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", ErrBech32Checksum},
+		{"BX1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", ErrBech32Checksum},
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6c", ErrCashAddrChecksum},
+		{"nosuchcoin:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", ErrUnknownAddressPrefix},
+	}
+	for _, tt := range tests {
+		if _, err := ParseAddress(tt.addr); !errors.Is(err, tt.want) {
+			t.Errorf("ParseAddress(%s) error = %v, want %v", tt.addr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Secp256k1 parameters.  See:
+//
+//	https://en.bitcoin.it/wiki/Secp256k1
+//	https://www.secg.org/sec2-v2.pdf - Section 2.4.1.
+var (
+	curveP = fromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+	curveN = fromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	curveG = Point{
+		fromHex("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		fromHex("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
+	}
+)
+
+var (
+	errInvalidPubKeyLen    = errors.New("invalid public key length")
+	errInvalidPubKeyFormat = errors.New("invalid public key format")
+	errPointNotOnCurve     = errors.New("the point does not lie on the elliptic curve")
+)
+
+func fromHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex in source: " + s)
+	}
+	return n
+}
+
+// CurveN returns the order of the base point G.
+func CurveN() *big.Int {
+	return new(big.Int).Set(curveN)
+}
+
+// CurveG returns a copy of the base point (or "generator point") G.
+func CurveG() Point {
+	return NewPoint().Set(curveG)
+}
+
+// Point is a point on the secp256k1 curve.  The point at infinity is
+// represented as (0, 0), which does not lie on the curve.
+type Point struct {
+	X, Y *big.Int
+}
+
+// NewPoint returns the point at infinity.
+func NewPoint() Point {
+	return Point{new(big.Int), new(big.Int)}
+}
+
+func (P Point) String() string {
+	return fmt.Sprintf("Point(%d, %d)", P.X, P.Y)
+}
+
+// Equals reports whether P and Q are the same point.
+func (P Point) Equals(Q Point) bool {
+	return P.X.Cmp(Q.X) == 0 && P.Y.Cmp(Q.Y) == 0
+}
+
+// Set copies the coordinates of Q into P and returns P.
+func (P Point) Set(Q Point) Point {
+	P.X.Set(Q.X)
+	P.Y.Set(Q.Y)
+	return P
+}
+
+// IsInfinity reports whether P is the point at infinity.
+func (P Point) IsInfinity() bool {
+	return P.X.Sign() == 0 && P.Y.Sign() == 0
+}
+
+// IsOnCurve checks that P satisfies y^2 = x^3 + 7 (mod p).
+func (P Point) IsOnCurve() bool {
+	if P.X.Sign() < 0 || P.X.Cmp(curveP) >= 0 ||
+		P.Y.Sign() < 0 || P.Y.Cmp(curveP) >= 0 {
+		return false
+	}
+	left := new(big.Int).Mul(P.Y, P.Y)
+	left.Mod(left, curveP)
+	right := new(big.Int).Mul(P.X, P.X)
+	right.Mul(right, P.X)
+	right.Add(right, big.NewInt(7))
+	right.Mod(right, curveP)
+	return left.Cmp(right) == 0
+}
+
+// Neg returns -P, the reflection of P across the x axis.
+func (P Point) Neg() Point {
+	if P.IsInfinity() {
+		return NewPoint()
+	}
+	y := new(big.Int).Sub(curveP, P.Y)
+	return Point{new(big.Int).Set(P.X), y}
+}
+
+// ECPointAdd sets R to P + Q and returns R.  Unlike the practice
+// versions in the main programs, the point at infinity and P + (-P)
+// are handled, since signature arithmetic relies on them.  See:
+//
+//	https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Point_addition
+//	https://crypto.stanford.edu/pbc/notes/elliptic/explicit.html
+func (R Point) ECPointAdd(P, Q Point) Point {
+	if P.IsInfinity() {
+		return R.Set(Q)
+	}
+	if Q.IsInfinity() {
+		return R.Set(P)
+	}
+	s := new(big.Int) // The slope
+	if P.X.Cmp(Q.X) == 0 {
+		if P.Y.Cmp(Q.Y) != 0 || P.Y.Sign() == 0 {
+			// P + (-P) is the point at infinity.
+			return R.Set(NewPoint())
+		}
+		// s = 3Px^2 / 2Py   mod p
+		s.Mul(big.NewInt(2), P.Y)
+		s.ModInverse(s, curveP)
+		s.Mul(s, big.NewInt(3))
+		s.Mul(s, P.X)
+		s.Mul(s, P.X)
+	} else {
+		// s = (Qy - Py) / (Qx - Px)   mod p
+		s.Sub(Q.X, P.X)
+		s.Mod(s, curveP)
+		s.ModInverse(s, curveP)
+		s.Mul(s, new(big.Int).Sub(Q.Y, P.Y))
+	}
+	x := new(big.Int)
+	y := new(big.Int)
+
+	// x = (s^2 - Px - Qx)   mod p
+	x.Mul(s, s)
+	x.Sub(x, P.X)
+	x.Sub(x, Q.X)
+	x.Mod(x, curveP)
+
+	// y = s*(Px - x) - Py   mod p
+	y.Sub(P.X, x)
+	y.Mul(y, s)
+	y.Sub(y, P.Y)
+	y.Mod(y, curveP)
+
+	return R.Set(Point{x, y})
+}
+
+// ECPointMul sets Q to d*P and returns Q.  This is an implimentation of
+// the Double-and-add algorithm with increasing index described here:
+//
+//	https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Double-and-add
+func (Q Point) ECPointMul(d *big.Int, P Point) Point {
+	k := new(big.Int).Mod(d, curveN)
+	N := NewPoint().Set(P)
+	Q.Set(NewPoint())
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			Q.ECPointAdd(Q, N)
+		}
+		N.ECPointAdd(N, N)
+	}
+	return Q
+}
+
+// ECBaseMul returns d*G.
+func ECBaseMul(d *big.Int) Point {
+	return NewPoint().ECPointMul(d, curveG)
+}
+
+// Serialize returns the 33-byte compressed serialization of the public
+// key.  See:
+//
+//	Mastering Bitcoin, pages 73-75.
+//	https://www.secg.org/sec1-v2.pdf - Section 2.3.3.
+func (R Point) Serialize() []byte {
+	b := make([]byte, 33)
+	b[0] = byte(2 + R.Y.Bit(0))
+	R.X.FillBytes(b[1:])
+	return b
+}
+
+// SerializeUncompressed returns the 65-byte 0x04 || x || y serialization
+// of the public key.
+func (R Point) SerializeUncompressed() []byte {
+	b := make([]byte, 65)
+	b[0] = 0x04
+	R.X.FillBytes(b[1:33])
+	R.Y.FillBytes(b[33:])
+	return b
+}
+
+// SerializeXOnly returns the 32-byte x coordinate used by BIP340 and
+// BIP341.
+func (R Point) SerializeXOnly() []byte {
+	b := make([]byte, 32)
+	R.X.FillBytes(b)
+	return b
+}
+
+// HasEvenY reports whether the y coordinate of R is even.
+func (R Point) HasEvenY() bool {
+	return R.Y.Bit(0) == 0
+}
+
+// liftX returns the point with the given x coordinate and an even y,
+// as defined by lift_x in BIP340.
+func liftX(x *big.Int) (Point, error) {
+	if x.Sign() < 0 || x.Cmp(curveP) >= 0 {
+		return Point{}, errPointNotOnCurve
+	}
+	// c = x^3 + 7, y = c^((p+1)/4)   mod p
+	c := new(big.Int).Mul(x, x)
+	c.Mul(c, x)
+	c.Add(c, big.NewInt(7))
+	c.Mod(c, curveP)
+	e := new(big.Int).Add(curveP, big.NewInt(1))
+	e.Rsh(e, 2)
+	y := new(big.Int).Exp(c, e, curveP)
+	if new(big.Int).Exp(y, big.NewInt(2), curveP).Cmp(c) != 0 {
+		return Point{}, errPointNotOnCurve
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(curveP, y)
+	}
+	return Point{new(big.Int).Set(x), y}, nil
+}
+
+// ParsePubKey parses a 33-byte compressed, 65-byte uncompressed or
+// 32-byte x-only public key.
+func ParsePubKey(b []byte) (Point, error) {
+	switch len(b) {
+	case 32:
+		return liftX(new(big.Int).SetBytes(b))
+	case 33:
+		if b[0] != 0x02 && b[0] != 0x03 {
+			return Point{}, errInvalidPubKeyFormat
+		}
+		P, err := liftX(new(big.Int).SetBytes(b[1:]))
+		if err != nil {
+			return Point{}, err
+		}
+		if b[0] == 0x03 {
+			P = P.Neg()
+		}
+		return P, nil
+	case 65:
+		if b[0] != 0x04 {
+			return Point{}, errInvalidPubKeyFormat
+		}
+		P := Point{new(big.Int).SetBytes(b[1:33]), new(big.Int).SetBytes(b[33:])}
+		if !P.IsOnCurve() {
+			return Point{}, errPointNotOnCurve
+		}
+		return P, nil
+	}
+	return Point{}, errInvalidPubKeyLen
+}
@@ -0,0 +1,87 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+var errInvalidPrivKeyLen = errors.New("private key must be 32 bytes")
+
+// PrivateKey is a secp256k1 private key in its canonical 32-byte big
+// endian form.  Unlike big.Int.Bytes, the encoding keeps leading zero
+// bytes, so a key with a small top byte still gives a valid WIF.  See:
+//
+//	https://en.bitcoin.it/wiki/Private_key#Range_of_valid_ECDSA_private_keys
+type PrivateKey [32]byte
+
+// NewPrivateKey returns the private key of scalar d, which must be in the
+// range 1..n-1.
+func NewPrivateKey(d *big.Int) (PrivateKey, error) {
+	var k PrivateKey
+	if !validPrivKey(d) {
+		return k, errInvalidPrivKey
+	}
+	d.FillBytes(k[:])
+	return k, nil
+}
+
+// PrivateKeyFromBytes returns the private key of a 32-byte big endian
+// scalar.
+func PrivateKeyFromBytes(b []byte) (PrivateKey, error) {
+	if len(b) != 32 {
+		return PrivateKey{}, errInvalidPrivKeyLen
+	}
+	return NewPrivateKey(new(big.Int).SetBytes(b))
+}
+
+// PrivateKeyFromWIF is DecodeWIF returning a PrivateKey.
+func PrivateKeyFromWIF(s string) (k PrivateKey, net *Network, compressed bool, err error) {
+	d, net, compressed, err := DecodeWIF(s)
+	if err != nil {
+		return k, nil, false, err
+	}
+	k, err = NewPrivateKey(d)
+	return k, net, compressed, err
+}
+
+// Bytes returns a copy of the 32-byte encoding.
+func (k PrivateKey) Bytes() []byte {
+	return append([]byte(nil), k[:]...)
+}
+
+// Int returns the key as a new big.Int.
+func (k PrivateKey) Int() *big.Int {
+	return new(big.Int).SetBytes(k[:])
+}
+
+// PublicKey returns the public key point d*G.
+func (k PrivateKey) PublicKey() Point {
+	return ECBaseMul(k.Int())
+}
+
+// WIF returns the Wallet Import Format encoding of the key for net, with
+// the 0x01 suffix when compressed is set.
+func (k PrivateKey) WIF(net *Network, compressed bool) string {
+	b := k.Bytes()
+	if compressed {
+		b = append(b, compressMagic)
+	}
+	return Base58CheckEncode(b, net.PrivateKeyID)
+}
+
+// Hex returns the 64-character hex encoding.
+func (k PrivateKey) Hex() string {
+	return hex.EncodeToString(k[:])
+}
+
+// Decimal returns the key as a decimal number.
+func (k PrivateKey) Decimal() string {
+	return k.Int().String()
+}
+
+// String returns the hex encoding, so that printing a key does not give
+// a byte array.
+func (k PrivateKey) String() string {
+	return k.Hex()
+}
@@ -0,0 +1,75 @@
+package kmdutil
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPrivateKeyWIF(t *testing.T) {
+	k, err := PrivateKeyFromBytes(mustHex(t, "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		net        *Network
+		compressed bool
+		want       string
+	}{
+		{BTCMainnet, false, "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"},
+		{BTCMainnet, true, "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"},
+	}
+	for _, tt := range tests {
+		wif := k.WIF(tt.net, tt.compressed)
+		if wif != tt.want {
+			t.Errorf("WIF(%s, %v) = %s, want %s", tt.net, tt.compressed, wif, tt.want)
+		}
+		got, net, compressed, err := PrivateKeyFromWIF(wif)
+		if err != nil || got != k || net != tt.net || compressed != tt.compressed {
+			t.Errorf("PrivateKeyFromWIF(%s) = %v, %v, %v, %v", wif, got, net, compressed, err)
+		}
+	}
+}
+
+func TestPrivateKeyLeadingZeros(t *testing.T) {
+	// A key below 2^248 loses its top byte in big.Int.Bytes, but must
+	// keep all 32 bytes in its WIF.
+	d := big.NewInt(1)
+	k, err := NewPrivateKey(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "0000000000000000000000000000000000000000000000000000000000000001"
+	if k.Hex() != want || k.String() != want || len(k.Bytes()) != 32 {
+		t.Errorf("NewPrivateKey(1) = %s, %d bytes, want %s", k.Hex(), len(k.Bytes()), want)
+	}
+	if k.Decimal() != "1" || k.Int().Cmp(d) != 0 {
+		t.Errorf("NewPrivateKey(1) = %s", k.Decimal())
+	}
+	for _, net := range []*Network{BTCMainnet, KMD} {
+		for _, compressed := range []bool{false, true} {
+			got, _, _, err := PrivateKeyFromWIF(k.WIF(net, compressed))
+			if err != nil || got != k {
+				t.Errorf("%s WIF round trip = %v, %v", net, got, err)
+			}
+		}
+	}
+	if !k.PublicKey().Equals(CurveG()) {
+		t.Errorf("PublicKey of 1 = %v, want G", k.PublicKey())
+	}
+}
+
+func TestPrivateKeyInvalid(t *testing.T) {
+	for _, d := range []*big.Int{big.NewInt(0), big.NewInt(-1), curveN, new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if _, err := NewPrivateKey(d); err != errInvalidPrivKey {
+			t.Errorf("NewPrivateKey(%x) error = %v, want %v", d, err, errInvalidPrivKey)
+		}
+	}
+	for _, n := range []int{0, 31, 33} {
+		if _, err := PrivateKeyFromBytes(make([]byte, n)); err != errInvalidPrivKeyLen {
+			t.Errorf("PrivateKeyFromBytes(%d bytes) error = %v, want %v", n, err, errInvalidPrivKeyLen)
+		}
+	}
+	if _, err := PrivateKeyFromBytes(make([]byte, 32)); err != errInvalidPrivKey {
+		t.Errorf("PrivateKeyFromBytes(zero) error = %v, want %v", err, errInvalidPrivKey)
+	}
+}
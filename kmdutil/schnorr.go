@@ -0,0 +1,134 @@
+package kmdutil
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var (
+	errInvalidPrivKey   = errors.New("private key must be in the range 1..n-1")
+	errInvalidSigLen    = errors.New("invalid signature length")
+	errInvalidAuxLen    = errors.New("auxiliary randomness must be 32 bytes")
+	errSignatureFailure = errors.New("produced signature does not verify")
+)
+
+// TaggedHash computes SHA256(SHA256(tag) || SHA256(tag) || msgs...) as
+// defined in BIP340.
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+// bytes32 returns the 32-byte big-endian encoding of n.
+func bytes32(n *big.Int) []byte {
+	b := make([]byte, 32)
+	n.FillBytes(b)
+	return b
+}
+
+// validPrivKey reports whether d is in the range 1..n-1.
+func validPrivKey(d *big.Int) bool {
+	return d.Sign() > 0 && d.Cmp(curveN) < 0
+}
+
+// schnorrChallenge computes e = int(hash_BIP0340/challenge(R.x || P.x || m)) mod n.
+func schnorrChallenge(rx, px, msg []byte) *big.Int {
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", rx, px, msg))
+	return e.Mod(e, curveN)
+}
+
+// schnorrNonce derives the BIP340 nonce for the (even-y adjusted) secret
+// key d.  Any extra data, such as an adaptor point, is committed to
+// after the message.
+func schnorrNonce(d *big.Int, px, msg, auxRand []byte, extra ...[]byte) *big.Int {
+	t := bytes32(d)
+	aux := TaggedHash("BIP0340/aux", auxRand)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+	msgs := append([][]byte{t, px, msg}, extra...)
+	k := new(big.Int).SetBytes(TaggedHash("BIP0340/nonce", msgs...))
+	return k.Mod(k, curveN)
+}
+
+// SchnorrPubKey returns the 32-byte x-only public key for d.
+func SchnorrPubKey(d *big.Int) ([]byte, error) {
+	if !validPrivKey(d) {
+		return nil, errInvalidPrivKey
+	}
+	return ECBaseMul(d).SerializeXOnly(), nil
+}
+
+// SchnorrSign creates a 64-byte BIP340 signature of msg with the private
+// key d.  auxRand must be 32 bytes of fresh randomness.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki#default-signing
+func SchnorrSign(d *big.Int, msg, auxRand []byte) ([]byte, error) {
+	if !validPrivKey(d) {
+		return nil, errInvalidPrivKey
+	}
+	if len(auxRand) != 32 {
+		return nil, errInvalidAuxLen
+	}
+	P := ECBaseMul(d)
+	dd := new(big.Int).Set(d)
+	if !P.HasEvenY() {
+		dd.Sub(curveN, dd)
+	}
+	px := P.SerializeXOnly()
+
+	k := schnorrNonce(dd, px, msg, auxRand)
+	if k.Sign() == 0 {
+		return nil, errSignatureFailure
+	}
+	R := ECBaseMul(k)
+	if !R.HasEvenY() {
+		k.Sub(curveN, k)
+	}
+	rx := R.SerializeXOnly()
+	e := schnorrChallenge(rx, px, msg)
+
+	// s = k + e*d   mod n
+	s := new(big.Int).Mul(e, dd)
+	s.Add(s, k)
+	s.Mod(s, curveN)
+
+	sig := append(rx, bytes32(s)...)
+	if !SchnorrVerify(px, msg, sig) {
+		return nil, errSignatureFailure
+	}
+	return sig, nil
+}
+
+// SchnorrVerify reports whether sig is a valid BIP340 signature of msg
+// for the 32-byte x-only public key pubKey.
+func SchnorrVerify(pubKey, msg, sig []byte) bool {
+	if len(pubKey) != 32 || len(sig) != 64 {
+		return false
+	}
+	P, err := liftX(new(big.Int).SetBytes(pubKey))
+	if err != nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(curveP) >= 0 || s.Cmp(curveN) >= 0 {
+		return false
+	}
+	e := schnorrChallenge(sig[:32], pubKey, msg)
+
+	// R = s*G - e*P
+	eP := NewPoint().ECPointMul(e, P)
+	R := NewPoint().ECPointAdd(ECBaseMul(s), eP.Neg())
+	if R.IsInfinity() || !R.HasEvenY() {
+		return false
+	}
+	return R.X.Cmp(r) == 0
+}
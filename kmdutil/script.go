@@ -0,0 +1,93 @@
+package kmdutil
+
+import "fmt"
+
+// Script opcodes used by the standard output templates.  See:
+//
+//	https://en.bitcoin.it/wiki/Script#Opcodes
+const (
+	OP_0             = 0x00
+	OP_DATA_20       = 0x14
+	OP_DATA_32       = 0x20
+	OP_DATA_33       = 0x21
+	OP_1             = 0x51
+	OP_16            = 0x60
+	OP_DUP           = 0x76
+	OP_EQUAL         = 0x87
+	OP_EQUALVERIFY   = 0x88
+	OP_HASH160       = 0xa9
+	OP_CHECKSIG      = 0xac
+	OP_CHECKMULTISIG = 0xae
+)
+
+// Script size limits.  A P2SH redeem script is pushed by the scriptSig, so
+// it is bound by the consensus limit on a single push; a P2WSH witness
+// script is not pushed and only bound by relay policy.
+const (
+	MaxScriptElementSize = 520
+	MaxP2WSHScriptSize   = 3600
+)
+
+// ScriptSizeError is returned for a redeem or witness script above its
+// size limit.
+type ScriptSizeError struct {
+	What      string
+	Size, Max int
+}
+
+func (e ScriptSizeError) Error() string {
+	return fmt.Sprintf("%s of %d bytes exceeds the %d-byte limit", e.What, e.Size, e.Max)
+}
+
+// P2PKHScript returns the scriptPubKey
+//
+//	OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+func P2PKHScript(pubKeyHash []byte) []byte {
+	script := []byte{OP_DUP, OP_HASH160, OP_DATA_20}
+	script = append(script, pubKeyHash...)
+	return append(script, OP_EQUALVERIFY, OP_CHECKSIG)
+}
+
+// P2SHScript returns the scriptPubKey
+//
+//	OP_HASH160 <scriptHash> OP_EQUAL
+func P2SHScript(scriptHash []byte) []byte {
+	script := []byte{OP_HASH160, OP_DATA_20}
+	script = append(script, scriptHash...)
+	return append(script, OP_EQUAL)
+}
+
+// P2WPKHScript returns the witness v0 key hash program
+//
+//	OP_0 <pubKeyHash>
+//
+// which is both the scriptPubKey of a native P2WPKH output and the
+// redeem script of a nested P2SH-P2WPKH output.
+func P2WPKHScript(pubKeyHash []byte) []byte {
+	return append([]byte{OP_0, OP_DATA_20}, pubKeyHash...)
+}
+
+// P2WSHScript returns the witness v0 script hash program
+//
+//	OP_0 <SHA256(witnessScript)>
+//
+// which is both the scriptPubKey of a native P2WSH output and the redeem
+// script of a nested P2SH-P2WSH output.
+func P2WSHScript(scriptHash []byte) []byte {
+	return append([]byte{OP_0, OP_DATA_32}, scriptHash...)
+}
+
+// WitnessScript returns the scriptPubKey of any witness output
+//
+//	OP_n <program>
+//
+// with OP_0 for version 0 and OP_1 to OP_16 for later versions, e.g.
+// OP_1 <outputKey> for P2TR.
+func WitnessScript(version byte, program []byte) []byte {
+	op := byte(OP_0)
+	if version > 0 {
+		op = OP_1 + version - 1
+	}
+	script := []byte{op, byte(len(program))}
+	return append(script, program...)
+}
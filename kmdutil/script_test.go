@@ -0,0 +1,49 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestStandardScripts(t *testing.T) {
+	hash := mustHex(t, "751e76e8199196d454941c45d1b3a323f1433bd6")
+	tests := []struct {
+		name   string
+		script []byte
+		want   string
+	}{
+		{"p2pkh", P2PKHScript(hash), "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{"p2sh", P2SHScript(hash), "a914751e76e8199196d454941c45d1b3a323f1433bd687"},
+		{"p2wpkh", P2WPKHScript(hash), "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"p2wsh", P2WSHScript(make([]byte, 32)), "00200000000000000000000000000000000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.script); got != tt.want {
+			t.Errorf("%s script = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWitnessScript(t *testing.T) {
+	// The scriptPubKeys of the valid addresses in BIP173 and BIP350.
+	tests := []struct {
+		address string
+		script  string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y",
+			"5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "5210751e76e8199196d454941c45d1b3a323"},
+	}
+	for _, tt := range tests {
+		_, version, program, err := DecodeSegWitAddress(tt.address)
+		if err != nil {
+			t.Errorf("DecodeSegWitAddress(%s): %v", tt.address, err)
+			continue
+		}
+		if got := hex.EncodeToString(WitnessScript(version, program)); got != tt.script {
+			t.Errorf("WitnessScript of %s = %s, want %s", tt.address, got, tt.script)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SegWit addresses.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0141.mediawiki#witness-program
+//	https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#segwit-address-format
+//	https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#addresses-for-segregated-witness-outputs
+
+var (
+	// ErrUncompressedPubKey is returned when an uncompressed public key
+	// is used for a witness output.  BIP143 only allows compressed keys.
+	ErrUncompressedPubKey = errors.New("witness outputs require a compressed public key")
+
+	// ErrWitnessVersion is returned for a witness version above 16.
+	ErrWitnessVersion = errors.New("invalid witness version")
+
+	// ErrWitnessVariant is returned when a witness version is encoded
+	// with the wrong checksum: Bech32 for v0, Bech32m for v1 and later.
+	ErrWitnessVariant = errors.New("invalid checksum variant for witness version")
+)
+
+// WitnessProgramLengthError is returned for a witness program of the
+// wrong size for its version.
+type WitnessProgramLengthError struct {
+	Version byte
+	Length  int
+}
+
+func (e WitnessProgramLengthError) Error() string {
+	return fmt.Sprintf("invalid witness v%d program length %d", e.Version, e.Length)
+}
+
+// checkWitnessProgram applies the BIP141 size rules.
+func checkWitnessProgram(version byte, program []byte) error {
+	if version > 16 {
+		return ErrWitnessVersion
+	}
+	if len(program) < 2 || len(program) > 40 ||
+		version == 0 && len(program) != 20 && len(program) != 32 {
+		return WitnessProgramLengthError{version, len(program)}
+	}
+	return nil
+}
+
+// EncodeSegWitAddress encodes a witness program as a SegWit address,
+// using Bech32 for witness v0 and Bech32m for later versions.
+func EncodeSegWitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := checkWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+	conv, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	variant := Bech32m
+	if version == 0 {
+		variant = Bech32
+	}
+	return Bech32Encode(hrp, append([]byte{version}, conv...), variant)
+}
+
+// DecodeSegWitAddress decodes a SegWit address into its HRP, witness
+// version and witness program.
+func DecodeSegWitAddress(addr string) (hrp string, version byte, program []byte, err error) {
+	hrp, data, variant, err := Bech32Decode(addr)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(data) < 1 {
+		return "", 0, nil, ErrWitnessVersion
+	}
+	version = data[0]
+	if (version == 0) != (variant == Bech32) {
+		return "", 0, nil, ErrWitnessVariant
+	}
+	program, err = ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if err := checkWitnessProgram(version, program); err != nil {
+		return "", 0, nil, err
+	}
+	return hrp, version, program, nil
+}
+
+// checkCompressedPubKey accepts only 33-byte 0x02/0x03 keys, as produced
+// by Point.Serialize.
+func checkCompressedPubKey(pubKey []byte) error {
+	if len(pubKey) == 65 && pubKey[0] == 0x04 {
+		return ErrUncompressedPubKey
+	}
+	if len(pubKey) != 33 {
+		return errInvalidPubKeyLen
+	}
+	if pubKey[0] != 0x02 && pubKey[0] != 0x03 {
+		return errInvalidPubKeyFormat
+	}
+	return nil
+}
+
+// segwitHRP returns the Bech32 HRP of net, or ErrNoSegWit.
+func segwitHRP(net *Network) (string, error) {
+	if !net.SupportsSegWit() {
+		return "", fmt.Errorf("%w: %s", ErrNoSegWit, net.Name)
+	}
+	return net.Bech32HRP, nil
+}
+
+// P2WPKHAddress returns the native SegWit (witness v0 key hash) address
+// of a compressed public key: bc1q..., tb1q..., bcrt1q... with the
+// 20-byte Hash160 of the key as the witness program.
+func P2WPKHAddress(pubKey []byte, net *Network) (string, error) {
+	if err := checkCompressedPubKey(pubKey); err != nil {
+		return "", err
+	}
+	hrp, err := segwitHRP(net)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSegWitAddress(hrp, 0, Hash160(pubKey))
+}
@@ -0,0 +1,101 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// BIP173 and BIP350 address vectors.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#test-vectors-for-v0-v16-native-segregated-witness-addresses
+func TestSegWitAddressValid(t *testing.T) {
+	tests := []struct {
+		addr, hrp, scriptPubKey string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "bc",
+			"0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "tb",
+			"00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", "bc",
+			"5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", "bc", "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "bc", "5210751e76e8199196d454941c45d1b3a323"},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "tb",
+			"0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "tb",
+			"5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "bc",
+			"512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+	for _, tt := range tests {
+		hrp, version, program, err := DecodeSegWitAddress(tt.addr)
+		if err != nil {
+			t.Errorf("DecodeSegWitAddress(%q): %v", tt.addr, err)
+			continue
+		}
+		got := hex.EncodeToString(WitnessScript(version, program))
+		if hrp != tt.hrp || got != tt.scriptPubKey {
+			t.Errorf("DecodeSegWitAddress(%q) = %s %s, want %s %s", tt.addr, hrp, got, tt.hrp, tt.scriptPubKey)
+		}
+		enc, err := EncodeSegWitAddress(hrp, version, program)
+		if err != nil || enc != strings.ToLower(tt.addr) {
+			t.Errorf("EncodeSegWitAddress round trip of %q = %q, %v", tt.addr, enc, err)
+		}
+	}
+}
+
+func TestSegWitAddressInvalid(t *testing.T) {
+	tests := []struct {
+		addr string
+		err  error
+	}{
+		// Witness v1 and later with a Bech32 checksum, v0 with Bech32m.
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", ErrWitnessVariant},
+		{"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", ErrWitnessVariant},
+		{"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", ErrWitnessVariant},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", ErrWitnessVariant},
+		{"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", ErrWitnessVariant},
+		{"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", Bech32CharError{59, 'o'}},
+		{"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", ErrWitnessVersion},
+		{"bc1pw5dgrnzv", WitnessProgramLengthError{1, 1}},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav",
+			WitnessProgramLengthError{1, 41}},
+		{"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", WitnessProgramLengthError{0, 16}},
+		{"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq", ErrBech32MixedCase},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf", ErrBech32Padding},
+		{"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j", ErrBech32Padding},
+		{"bc1gmk9yu", ErrWitnessVersion},
+	}
+	for _, tt := range tests {
+		_, _, _, err := DecodeSegWitAddress(tt.addr)
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("DecodeSegWitAddress(%q) error = %v, want %v", tt.addr, err, tt.err)
+		}
+	}
+}
+
+func TestP2WPKHAddress(t *testing.T) {
+	// The BIP173 example key, the public key of private key 1.
+	pub := mustHex(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	tests := []struct {
+		net  *Network
+		want string
+	}{
+		{BTCMainnet, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{BTCTestnet, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+	}
+	for _, tt := range tests {
+		got, err := P2WPKHAddress(pub, tt.net)
+		if err != nil || got != tt.want {
+			t.Errorf("P2WPKHAddress(%s) = %q, %v, want %q", tt.net, got, err, tt.want)
+		}
+	}
+	if _, err := P2WPKHAddress(CurveG().SerializeUncompressed(), BTCMainnet); err != ErrUncompressedPubKey {
+		t.Errorf("P2WPKHAddress(uncompressed) error = %v, want %v", err, ErrUncompressedPubKey)
+	}
+	if _, err := P2WPKHAddress(pub, KMD); err == nil {
+		t.Error("P2WPKHAddress accepted a network without SegWit")
+	}
+}
@@ -0,0 +1,177 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SLIP-132 extended key versions.  See:
+//
+//	https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+//
+// Electrum and hardware wallets replace the xpub/xprv version bytes to
+// record the script type of the addresses below the key, e.g. zpub for
+// native SegWit.  Only the version differs, so any of them converts to a
+// plain xpub and back without loss.  SLIP-132 only covers the SegWit
+// script types of Bitcoin and its testnets; Taproot keys and keys of
+// chains without SegWit have no such version.
+
+// ScriptType is the output script of the addresses derived from an
+// extended key.  The zero value is P2PKH, the plain xpub.
+type ScriptType int
+
+// Script types.  ScriptP2TR has no SLIP-132 version: String writes a
+// plain xpub, which parses back as ScriptP2PKH, and SLIP132String and
+// ConvertExtendedKey refuse it.
+const (
+	ScriptP2PKH      ScriptType = iota // xpub, tpub
+	ScriptP2SHP2WPKH                   // ypub, upub
+	ScriptP2SHP2WSH                    // Ypub, Upub (multisig)
+	ScriptP2WPKH                       // zpub, vpub
+	ScriptP2WSH                        // Zpub, Vpub (multisig)
+	ScriptP2TR
+)
+
+func (t ScriptType) String() string {
+	switch t {
+	case ScriptP2PKH:
+		return "p2pkh"
+	case ScriptP2SHP2WPKH:
+		return "p2sh-p2wpkh"
+	case ScriptP2SHP2WSH:
+		return "p2sh-p2wsh"
+	case ScriptP2WPKH:
+		return "p2wpkh"
+	case ScriptP2WSH:
+		return "p2wsh"
+	case ScriptP2TR:
+		return "p2tr"
+	}
+	return fmt.Sprintf("ScriptType(%d)", int(t))
+}
+
+// ErrMultisigScriptType is returned when asking a single extended key for
+// the address of a multisig script type, which needs the other co-signer
+// keys as well.
+var ErrMultisigScriptType = errors.New("script type needs a multisig script")
+
+// ErrNoSLIP132Version is returned when encoding a script type that has no
+// SLIP-132 version on the key's network, such as ScriptP2TR or any SegWit
+// type on Komodo, and a plain xpub would lose it.
+var ErrNoSLIP132Version = errors.New("no SLIP-132 version for script type")
+
+// slip132Version is one row of the SLIP-132 table: the public and private
+// versions of a script type on the networks whose plain versions are
+// base.
+type slip132Version struct {
+	base    [4]byte // plain public version, xpub or tpub
+	script  ScriptType
+	public  [4]byte
+	private [4]byte
+}
+
+var slip132Versions = []slip132Version{
+	{bip32Public, ScriptP2SHP2WPKH, [4]byte{0x04, 0x9d, 0x7c, 0xb2}, [4]byte{0x04, 0x9d, 0x78, 0x78}}, // ypub, yprv
+	{bip32Public, ScriptP2SHP2WSH, [4]byte{0x02, 0x95, 0xb4, 0x3f}, [4]byte{0x02, 0x95, 0xb0, 0x05}},  // Ypub, Yprv
+	{bip32Public, ScriptP2WPKH, [4]byte{0x04, 0xb2, 0x47, 0x46}, [4]byte{0x04, 0xb2, 0x43, 0x0c}},     // zpub, zprv
+	{bip32Public, ScriptP2WSH, [4]byte{0x02, 0xaa, 0x7e, 0xd3}, [4]byte{0x02, 0xaa, 0x7a, 0x99}},      // Zpub, Zprv
+	{tbip32Pub, ScriptP2SHP2WPKH, [4]byte{0x04, 0x4a, 0x52, 0x62}, [4]byte{0x04, 0x4a, 0x4e, 0x28}},   // upub, uprv
+	{tbip32Pub, ScriptP2SHP2WSH, [4]byte{0x02, 0x42, 0x89, 0xef}, [4]byte{0x02, 0x42, 0x85, 0xb5}},    // Upub, Uprv
+	{tbip32Pub, ScriptP2WPKH, [4]byte{0x04, 0x5f, 0x1c, 0xf6}, [4]byte{0x04, 0x5f, 0x18, 0xbc}},       // vpub, vprv
+	{tbip32Pub, ScriptP2WSH, [4]byte{0x02, 0x57, 0x54, 0x83}, [4]byte{0x02, 0x57, 0x50, 0x48}},        // Vpub, Vprv
+}
+
+// slip132Lookup returns the SLIP-132 row of a version, if any, and
+// whether the version is the private one.
+func slip132Lookup(v [4]byte) (row slip132Version, private, ok bool) {
+	for _, r := range slip132Versions {
+		if r.public == v || r.private == v {
+			return r, r.private == v, true
+		}
+	}
+	return slip132Version{}, false, false
+}
+
+// slip132Version returns the SLIP-132 version bytes of k's script type,
+// or false when there are none: for ScriptP2PKH, which uses the plain
+// version, for ScriptP2TR, and on networks without SegWit, whose keys
+// may share Bitcoin's xpub version but not its ypub and zpub.
+func (k *ExtendedKey) slip132Version() ([4]byte, bool) {
+	if k.Script == ScriptP2PKH || !k.Net.SupportsSegWit() {
+		return [4]byte{}, false
+	}
+	for _, r := range slip132Versions {
+		if r.base == k.Net.HDPublicKeyID && r.script == k.Script {
+			if k.IsPrivate() {
+				return r.private, true
+			}
+			return r.public, true
+		}
+	}
+	return [4]byte{}, false
+}
+
+// version returns the serialization version bytes of k: the SLIP-132
+// version of its script type, or the network's plain version when
+// SLIP-132 has none.
+func (k *ExtendedKey) version() [4]byte {
+	if v, ok := k.slip132Version(); ok {
+		return v
+	}
+	if k.IsPrivate() {
+		return k.Net.HDPrivateKeyID
+	}
+	return k.Net.HDPublicKeyID
+}
+
+// SLIP132String is String for callers that need the script type to
+// survive: it fails with ErrNoSLIP132Version instead of falling back to
+// a plain xpub for a script type other than ScriptP2PKH.
+func (k *ExtendedKey) SLIP132String() (string, error) {
+	if k.Script != ScriptP2PKH {
+		if _, ok := k.slip132Version(); !ok {
+			return "", fmt.Errorf("%w: %s on %s", ErrNoSLIP132Version, k.Script, k.Net)
+		}
+	}
+	return k.String(), nil
+}
+
+// WithScript returns a copy of k serialized with the version of script
+// type t, e.g. WithScript(ScriptP2PKH) turns a zpub into an xpub.
+func (k *ExtendedKey) WithScript(t ScriptType) *ExtendedKey {
+	c := *k
+	c.Script = t
+	return &c
+}
+
+// ConvertExtendedKey re-encodes a serialized extended key with the
+// version of script type t, keeping it private or public.  It fails with
+// ErrNoSLIP132Version when t has no version on the key's network.
+func ConvertExtendedKey(s string, t ScriptType) (string, error) {
+	k, err := ParseExtendedKey(s)
+	if err != nil {
+		return "", err
+	}
+	return k.WithScript(t).SLIP132String()
+}
+
+// Address returns the address of k's public key for its script type, the
+// encoder used for every key derived from a ypub, zpub and so on.
+func (k *ExtendedKey) Address() (string, error) {
+	var p Purpose
+	switch k.Script {
+	case ScriptP2PKH:
+		p = PurposeP2PKH
+	case ScriptP2SHP2WPKH:
+		p = PurposeP2SHP2WPKH
+	case ScriptP2WPKH:
+		p = PurposeP2WPKH
+	case ScriptP2TR:
+		p = PurposeP2TR
+	case ScriptP2SHP2WSH, ScriptP2WSH:
+		return "", fmt.Errorf("%w: %s", ErrMultisigScriptType, k.Script)
+	default:
+		return "", fmt.Errorf("unknown script type %s", k.Script)
+	}
+	return p.Address(k.PubKey(), k.Net)
+}
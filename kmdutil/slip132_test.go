@@ -0,0 +1,95 @@
+package kmdutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertExtendedKey(t *testing.T) {
+	// The BIP84 account key of the "abandon ... about" mnemonic in every
+	// SLIP-132 encoding.
+	encodings := []struct {
+		script ScriptType
+		key    string
+	}{
+		{ScriptP2PKH, "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"},
+		{ScriptP2SHP2WPKH, "ypub6XR9pJPUsVBFKweLeV85HtwdxjjmKEuUr6djm9mNdkh47X7ASsD6byaXFotRAKByFoWgSzCuoTjaYdrv2yoJroLAPtBuHFjVm5vNmhyNehE"},
+		{ScriptP2SHP2WSH, "Ypub6iKEwY7vSSjckWoib9b47yHSgXn2Xbb5ANHQgR2v1XXTjhg5DGbNh6ST4WqtikQsVGafKaD2gg85foUgLCxG1HSqEMJJgfCVepCFMi9sffi"},
+		{ScriptP2WPKH, "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"},
+		{ScriptP2WSH, "Zpub739WFCnqb8H6bozqRWNgL4NwrVvUUDaa5UodTovoPXuLnoVJTvkwKA6b5ioUif4ntuhU53ob9LUdZ66F3uNGoX8S6gzjGa1yvYFtkDRknR2"},
+	}
+	for _, from := range encodings {
+		k, err := ParseExtendedKey(from.key)
+		if err != nil {
+			t.Fatalf("ParseExtendedKey(%s): %v", from.key, err)
+		}
+		if k.Net != BTCMainnet || k.Script != from.script {
+			t.Errorf("ParseExtendedKey(%s) = %s %s, want btc %s", from.key[:4], k.Net, k.Script, from.script)
+		}
+		for _, to := range encodings {
+			if got, err := ConvertExtendedKey(from.key, to.script); err != nil || got != to.key {
+				t.Errorf("ConvertExtendedKey(%s, %s) = %s, %v, want %s", from.key[:4], to.script, got, err, to.key)
+			}
+		}
+	}
+
+	if _, err := ConvertExtendedKey(encodings[0].key, ScriptP2TR); !errors.Is(err, ErrNoSLIP132Version) {
+		t.Errorf("ConvertExtendedKey(p2tr) error = %v, want %v", err, ErrNoSLIP132Version)
+	}
+}
+
+func TestSLIP132Fallback(t *testing.T) {
+	master := abandonMaster(t, BTCTestnet)
+	account, err := master.DeriveAccount(PurposeP2WPKH, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := account.Neuter().String(); !strings.HasPrefix(s, "vpub") {
+		t.Errorf("testnet BIP84 account key = %s, want vpub...", s)
+	}
+
+	// Taproot and chains without SegWit fall back to the plain version in
+	// String, which SLIP132String reports as lossy.
+	if !BTCMainnet.SupportsSegWit() || KMD.SupportsSegWit() {
+		t.Errorf("SupportsSegWit = %v for btc, %v for kmd", BTCMainnet.SupportsSegWit(), KMD.SupportsSegWit())
+	}
+	tests := []struct {
+		net    *Network
+		script ScriptType
+	}{
+		{BTCMainnet, ScriptP2TR},
+		{KMD, ScriptP2SHP2WPKH},
+		{KMD, ScriptP2WPKH},
+		{KMD, ScriptP2WSH},
+	}
+	for _, tt := range tests {
+		k := abandonMaster(t, tt.net).WithScript(tt.script).Neuter()
+		if s := k.String(); !strings.HasPrefix(s, "xpub") {
+			t.Errorf("%s %s key = %s, want xpub...", tt.net, tt.script, s)
+		}
+		if _, err := k.SLIP132String(); !errors.Is(err, ErrNoSLIP132Version) {
+			t.Errorf("%s %s SLIP132String error = %v, want %v", tt.net, tt.script, err, ErrNoSLIP132Version)
+		}
+	}
+	if _, err := abandonMaster(t, KMD).SLIP132String(); err != nil {
+		t.Errorf("KMD xprv SLIP132String error = %v", err)
+	}
+}
+
+func TestExtendedKeyAddress(t *testing.T) {
+	k, err := ParseExtendedKey("zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := k.Derive("m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr, err := c.Address(); err != nil || addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Errorf("Address = %s, %v, want bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", addr, err)
+	}
+	if _, err := c.WithScript(ScriptP2WSH).Address(); !errors.Is(err, ErrMultisigScriptType) {
+		t.Errorf("Address(p2wsh) error = %v, want %v", err, ErrMultisigScriptType)
+	}
+}
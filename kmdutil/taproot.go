@@ -0,0 +1,94 @@
+package kmdutil
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Taproot (P2TR) key-path outputs.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+//	https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki
+
+var (
+	errInvalidMerkleRoot = errors.New("taproot merkle root must be empty or 32 bytes")
+	errInvalidTweak      = errors.New("taproot tweak is not a valid scalar")
+)
+
+// taprootTweak computes t = int(hash_TapTweak(P.x || merkleRoot)).  A nil
+// merkleRoot commits to a key-path only output.
+func taprootTweak(internalKeyX, merkleRoot []byte) (*big.Int, error) {
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, errInvalidMerkleRoot
+	}
+	t := new(big.Int).SetBytes(TaggedHash("TapTweak", internalKeyX, merkleRoot))
+	if t.Cmp(curveN) >= 0 {
+		return nil, errInvalidTweak
+	}
+	return t, nil
+}
+
+// TaprootOutputKey returns the tweaked output key Q = P + t*G for an
+// internal key given as a 32-byte x-only or a 33-byte compressed key.
+// Only the x coordinate of the internal key is used, as BIP341 requires.
+func TaprootOutputKey(internalKey, merkleRoot []byte) (Point, error) {
+	if len(internalKey) == 33 {
+		if err := checkCompressedPubKey(internalKey); err != nil {
+			return Point{}, err
+		}
+		internalKey = internalKey[1:]
+	}
+	if len(internalKey) != 32 {
+		return Point{}, errInvalidPubKeyLen
+	}
+	P, err := liftX(new(big.Int).SetBytes(internalKey))
+	if err != nil {
+		return Point{}, err
+	}
+	t, err := taprootTweak(internalKey, merkleRoot)
+	if err != nil {
+		return Point{}, err
+	}
+	Q := NewPoint().ECPointAdd(P, ECBaseMul(t))
+	if Q.IsInfinity() {
+		return Point{}, errInvalidTweak
+	}
+	return Q, nil
+}
+
+// TaprootTweakPrivKey returns the private key of the tweaked output key,
+// so that the output can be spent on the key path with SchnorrSign.
+func TaprootTweakPrivKey(d *big.Int, merkleRoot []byte) (*big.Int, error) {
+	if !validPrivKey(d) {
+		return nil, errInvalidPrivKey
+	}
+	P := ECBaseMul(d)
+	dd := new(big.Int).Set(d)
+	if !P.HasEvenY() {
+		dd.Sub(curveN, dd)
+	}
+	t, err := taprootTweak(P.SerializeXOnly(), merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	dd.Add(dd, t)
+	dd.Mod(dd, curveN)
+	if dd.Sign() == 0 {
+		return nil, errInvalidTweak
+	}
+	return dd, nil
+}
+
+// P2TRAddress returns the witness v1 address bc1p... of the output key
+// derived from internalKey and the optional script-tree merkleRoot.
+func P2TRAddress(internalKey, merkleRoot []byte, net *Network) (string, error) {
+	hrp, err := segwitHRP(net)
+	if err != nil {
+		return "", err
+	}
+	Q, err := TaprootOutputKey(internalKey, merkleRoot)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSegWitAddress(hrp, 1, Q.SerializeXOnly())
+}
@@ -0,0 +1,72 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// BIP341 wallet test vectors, scriptPubKey section.  See:
+//
+//	https://github.com/bitcoin/bips/blob/master/bip-0341/wallet-test-vectors.json
+func TestTaprootOutputKey(t *testing.T) {
+	tests := []struct {
+		internalKey, merkleRoot, outputKey, address string
+	}{
+		{
+			"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			"",
+			"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+			"bc1p2wsldez5mud2yam29q22wgfh9439spgduvct83k3pm50fcxa5dps59h4z5",
+		},
+		{
+			"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+			"bc1pz37fc4cn9ah8anwm4xqqhvxygjf9rjf2resrw8h8w4tmvcs0863sa2e586",
+		},
+		{
+			"93478e9488f956df2396be2ce6c5cced75f900dfa18e7dabd2428aae78451820",
+			"c525714a7f49c28aedbbba78c005931a81c234b2f6c99a73e4d06082adc8bf2b",
+			"e4d810fd50586274face62b8a807eb9719cef49c04177cc6b76a9a4251d5450e",
+			"bc1punvppl2stp38f7kwv2u2spltjuvuaayuqsthe34hd2dyy5w4g58qqfuag5",
+		},
+	}
+	for i, tt := range tests {
+		internal, root := mustHex(t, tt.internalKey), mustHex(t, tt.merkleRoot)
+		Q, err := TaprootOutputKey(internal, root)
+		if err != nil {
+			t.Fatalf("%d: TaprootOutputKey: %v", i, err)
+		}
+		if got := hex.EncodeToString(Q.SerializeXOnly()); got != tt.outputKey {
+			t.Errorf("%d: output key %s, want %s", i, got, tt.outputKey)
+		}
+		addr, err := P2TRAddress(internal, root, BTCMainnet)
+		if err != nil || addr != tt.address {
+			t.Errorf("%d: P2TRAddress = %q, %v, want %q", i, addr, err, tt.address)
+		}
+	}
+}
+
+func TestTaprootTweakPrivKey(t *testing.T) {
+	root := mustHex(t, "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21")
+	// Keys whose public keys have both an even and an odd y coordinate.
+	for _, d := range []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(0x1234567)} {
+		for _, merkleRoot := range [][]byte{nil, root} {
+			dd, err := TaprootTweakPrivKey(d, merkleRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			Q, err := TaprootOutputKey(ECBaseMul(d).Serialize(), merkleRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ECBaseMul(dd).Equals(Q) && !ECBaseMul(dd).Equals(Q.Neg()) {
+				t.Errorf("tweaked key of %d does not match the output key", d)
+			}
+		}
+	}
+	if _, err := TaprootOutputKey(CurveG().SerializeXOnly(), []byte{1, 2, 3}); err != errInvalidMerkleRoot {
+		t.Errorf("short merkle root error = %v, want %v", err, errInvalidMerkleRoot)
+	}
+}
@@ -0,0 +1,59 @@
+package kmdutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Tron addresses.  Tron derives the same 20 bytes as an EVM address,
+// Keccak-256(x || y)[12:], and writes them as Base58Check with a 0x41
+// prefix (T...), or as 42 hex digits starting with 41.  See:
+//
+//	https://developers.tron.network/docs/account#account-address-format
+
+const tronAddrID = 0x41
+
+// ErrTronAddress indicates a Tron address without the 0x41 prefix or
+// with a payload other than 20 bytes.
+var ErrTronAddress = errors.New("invalid tron address")
+
+// TronAddress returns the T... address of public key P.
+func TronAddress(P Point) string {
+	return Base58CheckEncode(evmAddressBytes(P), tronAddrID)
+}
+
+// TronAddressFromPubKey is TronAddress for a serialized public key,
+// either compressed or uncompressed.
+func TronAddressFromPubKey(pubKey []byte) (string, error) {
+	P, err := ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return TronAddress(P), nil
+}
+
+// TronBase58ToHex converts a T... address to its 41... hex form.
+func TronBase58ToHex(addr string) (string, error) {
+	version, payload, err := Base58CheckDecode(addr)
+	if err != nil {
+		return "", err
+	}
+	if version != tronAddrID || len(payload) != 20 {
+		return "", ErrTronAddress
+	}
+	return hex.EncodeToString(append([]byte{version}, payload...)), nil
+}
+
+// TronHexToBase58 converts a 41... hex address, with or without a 0x
+// prefix, to its T... form.
+func TronHexToBase58(s string) (string, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return "", err
+	}
+	if len(b) != 21 || b[0] != tronAddrID {
+		return "", ErrTronAddress
+	}
+	return Base58CheckEncode(b[1:], b[0]), nil
+}
@@ -0,0 +1,63 @@
+package kmdutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTronHexBase58(t *testing.T) {
+	// The example of the Tron account address documentation.
+	const (
+		hexAddr = "418840e6c55b9ada326d211d818c34a994aeced808"
+		b58Addr = "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL"
+	)
+	if got, err := TronHexToBase58(hexAddr); err != nil || got != b58Addr {
+		t.Errorf("TronHexToBase58(%s) = %s, %v, want %s", hexAddr, got, err, b58Addr)
+	}
+	if got, err := TronHexToBase58("0x" + strings.ToUpper(hexAddr)); err != nil || got != b58Addr {
+		t.Errorf("TronHexToBase58(0x...) = %s, %v, want %s", got, err, b58Addr)
+	}
+	if got, err := TronBase58ToHex(b58Addr); err != nil || got != hexAddr {
+		t.Errorf("TronBase58ToHex(%s) = %s, %v, want %s", b58Addr, got, err, hexAddr)
+	}
+}
+
+func TestTronAddress(t *testing.T) {
+	// Tron and EVM share the 20 bytes; private key 1 has the EVM
+	// address 0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf.
+	addr := TronAddress(CurveG())
+	if !strings.HasPrefix(addr, "T") {
+		t.Errorf("TronAddress(G) = %s, want T...", addr)
+	}
+	hexAddr, err := TronBase58ToHex(addr)
+	if err != nil || hexAddr != "41"+"7e5f4552091a69125d5dfcb7b8c2659029395bdf" {
+		t.Errorf("TronBase58ToHex(%s) = %s, %v", addr, hexAddr, err)
+	}
+	for _, pubKey := range [][]byte{CurveG().Serialize(), CurveG().SerializeUncompressed()} {
+		if got, err := TronAddressFromPubKey(pubKey); err != nil || got != addr {
+			t.Errorf("TronAddressFromPubKey(%x) = %s, %v, want %s", pubKey, got, err, addr)
+		}
+	}
+}
+
+func TestTronAddressInvalid(t *testing.T) {
+	for _, s := range []string{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",            // Bitcoin version byte
+		Base58CheckEncode(make([]byte, 21), tronAddrID), // 21-byte payload
+	} {
+		if _, err := TronBase58ToHex(s); err != ErrTronAddress {
+			t.Errorf("TronBase58ToHex(%s) error = %v, want %v", s, err, ErrTronAddress)
+		}
+	}
+	for _, s := range []string{
+		"008840e6c55b9ada326d211d818c34a994aeced808",
+		"418840e6c55b9ada326d211d818c34a994aeced8",
+	} {
+		if _, err := TronHexToBase58(s); err != ErrTronAddress {
+			t.Errorf("TronHexToBase58(%s) error = %v, want %v", s, err, ErrTronAddress)
+		}
+	}
+	if _, err := TronHexToBase58("41zz"); err == nil {
+		t.Error("TronHexToBase58 accepted non-hex input")
+	}
+}
@@ -0,0 +1,67 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Wallet Import Format (WIF) private keys.  See:
+//
+//	https://en.bitcoin.it/wiki/Wallet_import_format
+//
+//	WIF = Base58Check(version || key || [0x01])
+//
+// The trailing 0x01 marks a key whose public key is used compressed.
+
+const compressMagic = 0x01
+
+var (
+	// ErrWIFLength indicates a WIF payload that is neither 32 nor 33
+	// bytes long.
+	ErrWIFLength = errors.New("invalid WIF length")
+
+	// ErrWIFCompressFlag indicates a 33-byte WIF payload whose last byte
+	// is not 0x01.
+	ErrWIFCompressFlag = errors.New("invalid WIF compression flag")
+
+	// ErrUnknownWIFVersion is returned when no registered network has
+	// the version byte of a WIF.
+	ErrUnknownWIFVersion = errors.New("unknown WIF version byte")
+)
+
+// DecodeWIF decodes a WIF private key.  It returns the key scalar, the
+// first registered network with the WIF's version byte, and whether the
+// key is meant for a compressed public key.  Networks sharing a version
+// byte, such as 0x80 for Bitcoin and Zcash, resolve to the one registered
+// first.
+func DecodeWIF(s string) (d *big.Int, net *Network, compressed bool, err error) {
+	version, payload, err := Base58CheckDecode(s)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	switch len(payload) {
+	case 32:
+	case 33:
+		if payload[32] != compressMagic {
+			return nil, nil, false, ErrWIFCompressFlag
+		}
+		compressed = true
+	default:
+		return nil, nil, false, ErrWIFLength
+	}
+	for _, n := range Networks() {
+		if n.PrivateKeyID == version {
+			net = n
+			break
+		}
+	}
+	if net == nil {
+		return nil, nil, false, fmt.Errorf("%w: 0x%02x", ErrUnknownWIFVersion, version)
+	}
+	d = new(big.Int).SetBytes(payload[:32])
+	if !validPrivKey(d) {
+		return nil, nil, false, errInvalidPrivKey
+	}
+	return d, net, compressed, nil
+}
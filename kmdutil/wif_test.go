@@ -0,0 +1,69 @@
+package kmdutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestDecodeWIF(t *testing.T) {
+	const (
+		btcKey = "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"
+		kmdKey = "907ece717a8f94e07de7bf6f8b3e9f91abb8858ebf831072cdbb9016ef53bc5d"
+	)
+	tests := []struct {
+		wif        string
+		key        string
+		net        *Network
+		compressed bool
+	}{
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", btcKey, BTCMainnet, false},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", btcKey, BTCMainnet, true},
+		{"7KYb75jv5BgrDCbmW36yhofiBy2vSLpCCWDfJ9dMdZxPWnKicJh", kmdKey, KMD, false},
+		{"UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh", kmdKey, KMD, true},
+	}
+	for _, tt := range tests {
+		d, net, compressed, err := DecodeWIF(tt.wif)
+		if err != nil {
+			t.Errorf("DecodeWIF(%s): %v", tt.wif, err)
+			continue
+		}
+		if got := fmt.Sprintf("%064x", d); got != tt.key || net != tt.net || compressed != tt.compressed {
+			t.Errorf("DecodeWIF(%s) = %s, %v, %v, want %s, %v, %v",
+				tt.wif, got, net, compressed, tt.key, tt.net, tt.compressed)
+		}
+	}
+}
+
+func TestDecodeWIFInvalid(t *testing.T) {
+	key := make([]byte, 32)
+	key[31] = 1
+	tests := []struct {
+		name string
+		wif  string
+		want error
+	}{
+		{"short", Base58CheckEncodePrefix(key[:31], []byte{0x80}), ErrWIFLength},
+		{"long", Base58CheckEncodePrefix(append(key, 0x01, 0x01), []byte{0x80}), ErrWIFLength},
+		{"flag", Base58CheckEncodePrefix(append(key, 0x02), []byte{0x80}), ErrWIFCompressFlag},
+		{"version", Base58CheckEncodePrefix(key, []byte{0x99}), ErrUnknownWIFVersion},
+		{"zero", Base58CheckEncodePrefix(make([]byte, 32), []byte{0x80}), errInvalidPrivKey},
+		{"order", Base58CheckEncodePrefix(curveN.Bytes(), []byte{0x80}), errInvalidPrivKey},
+		{"checksum", "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTK", ErrChecksum},
+	}
+	for _, tt := range tests {
+		if _, _, _, err := DecodeWIF(tt.wif); !errors.Is(err, tt.want) {
+			t.Errorf("DecodeWIF(%s) error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// Keys 1 and n-1 are the ends of the valid range.
+	for _, d := range []*big.Int{big.NewInt(1), new(big.Int).Sub(curveN, big.NewInt(1))} {
+		b := make([]byte, 32)
+		d.FillBytes(b)
+		if got, _, _, err := DecodeWIF(Base58CheckEncodePrefix(b, []byte{0x80})); err != nil || got.Cmp(d) != 0 {
+			t.Errorf("DecodeWIF(%x) = %v, %v", b, got, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package kmdutil
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// XRP Ledger classic addresses and family seeds.  See:
+//
+//	https://xrpl.org/addresses.html#address-encoding
+//	https://xrpl.org/cryptographic-keys.html#secp256k1-key-derivation
+//
+// Both are Base58Check in the Ripple alphabet: an address is version 0x00
+// and the Hash160 account ID (r...), a seed is version 0x21 and 16 bytes
+// of entropy (s...).
+
+const (
+	xrpAccountID = 0x00
+	xrpSeedID    = 0x21
+)
+
+var (
+	// ErrXRPAddress indicates an XRP address with the wrong version byte
+	// or an account ID other than 20 bytes.
+	ErrXRPAddress = errors.New("invalid XRP address")
+
+	// ErrXRPSeed indicates an XRP seed with the wrong version byte or
+	// entropy other than 16 bytes.
+	ErrXRPSeed = errors.New("invalid XRP seed")
+)
+
+// XRPAddress returns the classic r... address of a compressed secp256k1
+// public key.
+func XRPAddress(pubKey []byte) (string, error) {
+	if err := checkCompressedPubKey(pubKey); err != nil {
+		return "", err
+	}
+	return RippleAlphabet.CheckEncode(Hash160(pubKey), []byte{xrpAccountID}), nil
+}
+
+// DecodeXRPAddress returns the 20-byte account ID of a classic address.
+func DecodeXRPAddress(addr string) ([]byte, error) {
+	version, accountID, err := RippleAlphabet.CheckDecode(addr, 1)
+	if err != nil {
+		return nil, err
+	}
+	if version[0] != xrpAccountID || len(accountID) != 20 {
+		return nil, ErrXRPAddress
+	}
+	return accountID, nil
+}
+
+// EncodeXRPSeed returns the s... family seed of 16 bytes of entropy.
+func EncodeXRPSeed(entropy []byte) (string, error) {
+	if len(entropy) != 16 {
+		return "", ErrXRPSeed
+	}
+	return RippleAlphabet.CheckEncode(entropy, []byte{xrpSeedID}), nil
+}
+
+// DecodeXRPSeed returns the 16 bytes of entropy of a family seed.
+func DecodeXRPSeed(seed string) ([]byte, error) {
+	version, entropy, err := RippleAlphabet.CheckDecode(seed, 1)
+	if err != nil {
+		return nil, err
+	}
+	if version[0] != xrpSeedID || len(entropy) != 16 {
+		return nil, ErrXRPSeed
+	}
+	return entropy, nil
+}
+
+// XRPSeedFromPassphrase returns the seed entropy rippled derives from a
+// passphrase: the first 16 bytes of SHA-512(passphrase).
+func XRPSeedFromPassphrase(passphrase string) []byte {
+	h := sha512.Sum512([]byte(passphrase))
+	return h[:16]
+}
+
+// xrpScalar returns the first valid SHA-512Half(data || seq) for seq =
+// 0, 1, ..., as rippled does for both derivation steps.
+func xrpScalar(data []byte) *big.Int {
+	buf := make([]byte, len(data)+4)
+	copy(buf, data)
+	for seq := uint32(0); ; seq++ {
+		binary.BigEndian.PutUint32(buf[len(data):], seq)
+		h := sha512.Sum512(buf)
+		k := new(big.Int).SetBytes(h[:32])
+		if validPrivKey(k) {
+			return k
+		}
+	}
+}
+
+// XRPSeedKey derives the secp256k1 key of account 0 from seed entropy:
+// the root key from the seed, plus an intermediate scalar from the root
+// public key.
+func XRPSeedKey(entropy []byte) (PrivateKey, error) {
+	if len(entropy) != 16 {
+		return PrivateKey{}, ErrXRPSeed
+	}
+	root := xrpScalar(entropy)
+	rootPub := ECBaseMul(root).Serialize()
+	t := xrpScalar(append(rootPub, 0, 0, 0, 0)) // account index 0
+	d := new(big.Int).Add(root, t)
+	return NewPrivateKey(d.Mod(d, curveN))
+}
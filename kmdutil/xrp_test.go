@@ -0,0 +1,88 @@
+package kmdutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestXRPMasterPassphrase(t *testing.T) {
+	// The genesis account of the XRP Ledger.
+	const (
+		seed    = "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+		address = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	)
+	entropy := XRPSeedFromPassphrase("masterpassphrase")
+	if got, err := EncodeXRPSeed(entropy); err != nil || got != seed {
+		t.Errorf("EncodeXRPSeed = %s, %v, want %s", got, err, seed)
+	}
+	if got, err := DecodeXRPSeed(seed); err != nil || !bytes.Equal(got, entropy) {
+		t.Errorf("DecodeXRPSeed(%s) = %x, %v, want %x", seed, got, err, entropy)
+	}
+	k, err := XRPSeedKey(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := XRPAddress(k.PublicKey().Serialize())
+	if err != nil || got != address {
+		t.Errorf("XRPAddress = %s, %v, want %s", got, err, address)
+	}
+	accountID, err := DecodeXRPAddress(address)
+	if err != nil || !bytes.Equal(accountID, Hash160(k.PublicKey().Serialize())) {
+		t.Errorf("DecodeXRPAddress(%s) = %x, %v", address, accountID, err)
+	}
+}
+
+func TestXRPInvalid(t *testing.T) {
+	if _, err := DecodeXRPAddress("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTj"); err != ErrChecksum {
+		t.Errorf("DecodeXRPAddress(bad checksum) error = %v, want %v", err, ErrChecksum)
+	}
+	// A seed is a valid Ripple Base58Check string, but not an address,
+	// and the other way round.
+	if _, err := DecodeXRPAddress("snoPBrXtMeMyMHUVTgbuqAfg1SUTb"); err != ErrXRPAddress {
+		t.Errorf("DecodeXRPAddress(seed) error = %v, want %v", err, ErrXRPAddress)
+	}
+	if _, err := DecodeXRPSeed("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"); err != ErrXRPSeed {
+		t.Errorf("DecodeXRPSeed(address) error = %v, want %v", err, ErrXRPSeed)
+	}
+	if _, err := EncodeXRPSeed(make([]byte, 15)); err != ErrXRPSeed {
+		t.Errorf("EncodeXRPSeed(15 bytes) error = %v, want %v", err, ErrXRPSeed)
+	}
+	if _, err := XRPSeedKey(make([]byte, 17)); err != ErrXRPSeed {
+		t.Errorf("XRPSeedKey(17 bytes) error = %v, want %v", err, ErrXRPSeed)
+	}
+	if _, err := XRPAddress(CurveG().SerializeUncompressed()); err != ErrUncompressedPubKey {
+		t.Errorf("XRPAddress(uncompressed) error = %v, want %v", err, ErrUncompressedPubKey)
+	}
+}
+
+func TestBase58Alphabets(t *testing.T) {
+	// The same Hash160 gives 1... in Bitcoin's alphabet and r... in
+	// Ripple's, digit for digit.
+	const btc = "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"
+	b, err := BitcoinAlphabet.Decode(btc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xrp := RippleAlphabet.Encode(b)
+	for i := 0; i < len(btc); i++ {
+		digit := strings.IndexByte(BitcoinAlphabet.String(), btc[i])
+		if xrp[i] != RippleAlphabet.String()[digit] {
+			t.Fatalf("Ripple encoding %s does not map %s digit by digit", xrp, btc)
+		}
+	}
+	if got, err := RippleAlphabet.Decode(xrp); err != nil || !bytes.Equal(got, b) {
+		t.Errorf("RippleAlphabet.Decode(%s) = %x, %v, want %x", xrp, got, err, b)
+	}
+	if got := FlickrAlphabet.Encode([]byte{0, 0, 57}); got != "11Z" {
+		t.Errorf("FlickrAlphabet.Encode = %s, want 11Z", got)
+	}
+
+	for _, chars := range []string{
+		"", BitcoinAlphabet.String()[:57], BitcoinAlphabet.String()[:57] + "1",
+	} {
+		if _, err := NewBase58Alphabet(chars); err != ErrBase58Alphabet {
+			t.Errorf("NewBase58Alphabet(%q) error = %v, want %v", chars, err, ErrBase58Alphabet)
+		}
+	}
+}
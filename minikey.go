@@ -0,0 +1,45 @@
+package main
+
+// Casascius mini private key practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+//
+//	go run minikey.go                                   # new random mini key
+//	go run minikey.go S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy    # check a mini key
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	var miniKey string
+	var privKey kmdutil.PrivateKey
+	var err error
+	if len(os.Args) > 1 {
+		miniKey = os.Args[1]
+		privKey, err = kmdutil.ParseMiniKey(miniKey)
+	} else {
+		miniKey, privKey, err = kmdutil.GenerateMiniKey(30, nil)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	/*
+	 * The mini key is only shorthand for its SHA-256, which then goes
+	 * through the usual WIF and address steps.  Mini keys are always used
+	 * with uncompressed public keys.
+	 */
+	fmt.Println("mini key:", miniKey)
+	fmt.Println("private key:", privKey.Hex())
+	pubKey := privKey.PublicKey().SerializeUncompressed()
+	for _, net := range []*kmdutil.Network{kmdutil.BTCMainnet, kmdutil.KMD} {
+		fmt.Printf("%s WIF: %s  address: %s\n", net, privKey.WIF(net, false), kmdutil.P2PKHAddress(pubKey, net))
+	}
+}
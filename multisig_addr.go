@@ -0,0 +1,97 @@
+package main
+
+// m-of-n multisig address generation practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	passStrs := []string{
+		"myverysecretandstrongpassphrase_one",
+		"myverysecretandstrongpassphrase_two",
+		"myverysecretandstrongpassphrase_three",
+	}
+
+	// Each co-signer's private key, from the passphrases above or from
+	// WIFs given on the command line:
+	//     go run multisig_addr.go <wif1> <wif2> <wif3>
+	// An uncompressed WIF (5... or 7...) contributes its uncompressed
+	// public key, which only the P2SH address accepts.
+	var privateKeys []*big.Int
+	var compressed []bool
+	for _, passStr := range passStrs {
+		passHash := sha256.Sum256([]byte(passStr))
+		privateKeys = append(privateKeys, new(big.Int).SetBytes(passHash[:]))
+		compressed = append(compressed, true)
+	}
+	if len(os.Args) > 1 {
+		privateKeys, compressed = nil, nil
+		for _, wif := range os.Args[1:] {
+			privateKey, _, wifCompressed, err := kmdutil.DecodeWIF(wif)
+			if err != nil {
+				log.Fatalf("%s: %v", wif, err)
+			}
+			privateKeys = append(privateKeys, privateKey)
+			compressed = append(compressed, wifCompressed)
+		}
+	}
+
+	// Each co-signer's public key, compressed as produced by Serialize()
+	// unless its WIF says otherwise.
+	var pubKeys [][]byte
+	for i, privateKey := range privateKeys {
+		publicKey := kmdutil.ECBaseMul(privateKey)
+		pubKey := publicKey.Serialize()
+		if !compressed[i] {
+			pubKey = publicKey.SerializeUncompressed()
+		}
+		pubKeys = append(pubKeys, pubKey)
+		fmt.Printf("pubkey: %x\n", pubKey)
+	}
+	fmt.Println()
+
+	/*
+	 * 2-of-3 redeem script with the keys sorted as described in BIP67, so
+	 * every co-signer builds the same script regardless of key order.
+	 *     OP_2 <pubkey> <pubkey> <pubkey> OP_3 OP_CHECKMULTISIG
+	 */
+	redeemScript, err := kmdutil.MultisigScript(2, pubKeys, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("redeem script: %x\n\n", redeemScript)
+
+	for _, net := range kmdutil.Networks() {
+		p2shAddress, err := kmdutil.P2SHAddress(redeemScript, net)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s P2SH address: %s\n", net, p2shAddress)
+		if net.Bech32HRP == "" {
+			continue
+		}
+		p2wshAddress, err := kmdutil.P2WSHAddress(redeemScript, net)
+		if err != nil {
+			fmt.Printf("%s P2WSH address error: %v\n", net, err)
+			continue
+		}
+		fmt.Printf("%s P2WSH address: %s\n", net, p2wshAddress)
+		p2shP2wshAddress, err := kmdutil.P2SHP2WSHAddress(redeemScript, net)
+		if err != nil {
+			fmt.Printf("%s P2SH-P2WSH address error: %v\n", net, err)
+			continue
+		}
+		fmt.Printf("%s P2SH-P2WSH address: %s\n", net, p2shP2wshAddress)
+	}
+}
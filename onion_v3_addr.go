@@ -4,18 +4,17 @@ package main
 // converting python code example from Gareth's gareth_file04.py
 
 import (
-	"bytes"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base32"
 	"fmt"
-	"strings"
 
 	// "log"
 	"math/big"
+	"os"
 
+	"btc-practice/kmdutil"
 	"golang.org/x/crypto/ripemd160"
-	"golang.org/x/crypto/sha3"
 )
 
 type Point struct {
@@ -146,7 +145,7 @@ func (Q *Point) ec_point_multiply(d *big.Int, P *Point) Point {
 func (R Point) Serialize() []byte {
 	b := R.x.Bytes()
 	fmt.Println("Check if Y is Even or Odd: ")
-	fmt.Println("R.y.Mod(big.NewInt(2))", R.y.Mod(R.y, big.NewInt(2)))
+	fmt.Println("R.y.Mod(big.NewInt(2))", new(big.Int).Mod(R.y, big.NewInt(2)))
 
 	// If the length of Public Key x bytes is lesser than 32 bytes, we need to add
 	// the required remaining bytes to the Public Key x.
@@ -169,7 +168,7 @@ func (R Point) Serialize() []byte {
 		        fmt.Println(n,"is Odd number")
 		    }
 	*/
-	if R.y.Mod(R.y, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
+	if R.y.Bit(0) == 0 {
 		fmt.Println(R.y, "is Even number")
 		fmt.Println("R.x", R.x)
 		fmt.Println("R.x Bytes", b)
@@ -204,47 +203,10 @@ func s256(data []byte) []byte {
 	return h.Sum(nil)
 }
 
-/*
- * Encode the data in Bitcoin's Base58 format.  See:
- *     https://en.bitcoin.it/wiki/Base58Check_encoding#Base58_symbol_chart
- */
-func base58(data []byte) string {
-	base := big.NewInt(58)                                                        // set new big int for dividing data bytes with 58
-	remainder := new(big.Int)                                                     // to store remainder of the data bytes
-	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz" // base 58 character set
-
-	//fmt.Println("Length of base58 characters: ", len(alphabet))
-	//fmt.Printf("Recieved data bytes: %x\n", data)
-
-	x := new(big.Int).SetBytes(data) // convert bytes to big integer
-	//fmt.Println("Bytes to big integer: ", x)
-	var output_string []byte // To store base58 coverted value to a temp variable
-
-	for x.Cmp(big.NewInt(0)) != 0 {
-		x.DivMod(x, base, remainder)
-		//fmt.Println("X", x, "\nRemainder", remainder)
-		//fmt.Printf("alphabet[%d]: %s\n", remainder.Int64(), string(alphabet[remainder.Int64()]))
-		output_string = append(output_string, alphabet[remainder.Int64()]) // Appending the value stored at position alphabet[remainder] to output_string
-	}
-	//fmt.Println("output_string value after first loop: ", string(output_string))
-
-	for i := 0; data[i] == 0; i++ {
-		output_string = append(output_string, alphabet[0])
-	}
-	//fmt.Println("output_string value after second loop: ", string(output_string))
-
-	// Reverse the array.
-	for i, j := 0, len(output_string)-1; i < j; i, j = i+1, j-1 {
-		output_string[i], output_string[j] = output_string[j], output_string[i]
-	}
-	return string(output_string)
-}
-
 func main() {
-	// btcVersionByte := []byte{0x0}
-	// btcPrivKeyVersionByte := []byte{0x80}
-	kmdVersionByte := []byte{0x3C}
-	kmdPrivKeyVersionByte := []byte{0xBC}
+	// The chain to generate the address and WIF for.  Any network from
+	// the registry works, e.g. kmdutil.BTCMainnet or kmdutil.LTC.
+	net := kmdutil.KMD
 	// Please note that the following code is a demo.  Edge cases and error
 	// checking are intentionally omitted where they might otherwise distract
 	// us from the core ideas.
@@ -288,13 +250,38 @@ func main() {
 	fmt.Println("Password/Passphrase: ", passStr)
 	fmt.Printf("password hash: %x\n", passHash)
 
+	// A WIF given on the command line replaces the passphrase key, and its
+	// version byte selects the network, e.g.
+	//     go run onion_v3_addr.go UtrRXqvRFUAtCrCTRAHPH6yroQKUrrTJRmxt2h5U4QTUN1jCxTAh
+	// An uncompressed WIF (5... or 7...) stands for the uncompressed public
+	// key, so its address hashes that instead.
+	compressed := true
+	if len(os.Args) > 1 {
+		wifKey, wifNet, wifCompressed, err := kmdutil.DecodeWIF(os.Args[1])
+		if err != nil {
+			fmt.Println("WIF decode error:", err)
+			return
+		}
+		private_key, net, compressed = wifKey, wifNet, wifCompressed
+		fmt.Println("WIF:", os.Args[1], "network:", net, "compressed:", compressed)
+	}
+
 	// Mastering Bitcoin example privkey, which has even public key x value
 	// private_key, ok := private_key.SetString("038109007313a5807b2eccc082c8c3fbb988a973cacf1a7df9ce725c31b14776", 16)
 	// if !ok {
 	// 	log.Fatalf("big Int value did not set")
 	// 	//return errors.New("big Int value did not set")
 	// }
-	fmt.Printf("private_key: %d\n", private_key)
+	// PrivateKey keeps the canonical 32-byte form, where
+	// private_key.Bytes() would drop leading zero bytes and give a short,
+	// invalid WIF.
+	privKey, err := kmdutil.NewPrivateKey(private_key)
+	if err != nil {
+		fmt.Println("private key error:", err)
+		return
+	}
+	fmt.Println("private_key:", privKey.Decimal())
+	fmt.Println("private_key hex:", privKey.Hex())
 
 	var G Point
 	G = ec_G()
@@ -302,11 +289,14 @@ func main() {
 	fmt.Printf("Gy: %d\n", G.y)
 
 	var publicKey Point
-	publicKey.ec_point_multiply(private_key, &G)
+	publicKey.ec_point_multiply(privKey.Int(), &G)
 	fmt.Printf("\npublicKey.x %d\n", publicKey.x)
 	fmt.Printf("publicKey.y %d\n", publicKey.y)
 
 	serializedPublicKey := publicKey.Serialize()
+	if !compressed {
+		serializedPublicKey = kmdutil.Point{X: publicKey.x, Y: publicKey.y}.SerializeUncompressed()
+	}
 	fmt.Printf("serializedPublicKey: %x\n", serializedPublicKey)
 
 	publicKeyHash := r160(s256(serializedPublicKey))
@@ -321,23 +311,12 @@ func main() {
 	onionPubKey := onionPrivKey.Public()
 	fmt.Printf("onionPubKey: %x\n", onionPubKey)
 
+	// onion_address = base32(pubkey || checksum || version), where
 	// checksum = H(".onion checksum" || pubkey || version)
-	var onionChecksumBytes bytes.Buffer
-	onionChecksumBytes.Write([]byte(".onion checksum"))
-	onionChecksumBytes.Write([]byte(onionPubKey.(ed25519.PublicKey)))
-	onionChecksumBytes.Write([]byte{0x03})
-	onionChecksum := sha3.Sum256(onionChecksumBytes.Bytes())
-	fmt.Printf("onionChecksum %x\n", onionChecksum)
-
-	// onion_address = base32(pubkey || checksum || version)
-	var onionAddressBytes bytes.Buffer
-	onionAddressBytes.Write([]byte(onionPubKey.(ed25519.PublicKey)))
-	onionAddressBytes.Write([]byte(onionChecksum[:2]))
-	onionAddressBytes.Write([]byte{0x03})
-	onionAddress := base32.StdEncoding.EncodeToString(onionAddressBytes.Bytes())
+	onionAddress := kmdutil.OnionV3Address(onionPubKey.(ed25519.PublicKey))
 
 	fmt.Println("onionPubKey:", base32.StdEncoding.EncodeToString([]byte(onionPubKey.(ed25519.PublicKey))))
-	fmt.Println("onionAddress:", strings.ToLower(onionAddress)+".onion", "\n\n")
+	fmt.Println("onionAddress:", onionAddress, "\n\n")
 
 	/*
 	 * Calculate the checksum needed for Bitcoin's Base58Check
@@ -345,7 +324,7 @@ func main() {
 	 *     Mastering Bitcoin, page 58
 	 *     https://en.bitcoin.it/wiki/Technical_background_of_version_1_Bitcoin_addresses#How_to_create_Bitcoin_Address - Steps 5-7.
 	 */
-	version := kmdVersionByte
+	version := append([]byte{}, net.PubKeyHashAddrID...)
 	fmt.Printf("Bitcoin version byte: %d\n", version)
 	versionPlusHash := append(version, publicKeyHash...)
 	fmt.Printf("bitcoin version + pubkey hash: %d\n", versionPlusHash)
@@ -357,39 +336,33 @@ func main() {
 	 * Bitcoin's Base58Check format.  See:
 	 *     Mastering Bitcoin, page 66.
 	 */
-	address := base58(append(versionPlusHash, checksum...))
+	address := kmdutil.Base58Encode(append(versionPlusHash, checksum...))
 	fmt.Println("address:", address, "\n")
 
 	/*
+	 * Wallet Import Format: the version byte and the 32-byte key in
+	 * Base58Check, with an extra 0x01 byte before the checksum when the
+	 * key is used with a compressed public key.  See:
 	 *	https://www.mobilefish.com/services/cryptocurrency/cryptocurrency.html#refPrivateKeyHex
+	 *     https://en.bitcoin.it/wiki/Wallet_import_format
 	 */
-	privKeyVersion := kmdPrivKeyVersionByte                              // version byte to add as prefix for private key
-	privKeyPlusVersion := append(privKeyVersion, private_key.Bytes()...) // privkey version + privkey hash
-	privKeyChecksum := s256(s256(privKeyPlusVersion))[:4]                // first 4 bytes of double hashed (privkey version + privkey hash)
-	fmt.Printf("Bitcoin Private Key version byte: %d\n", privKeyVersion)
-	fmt.Printf("bitcoin version + private key hash: %x   byte length: %d\n", privKeyPlusVersion, len(privKeyPlusVersion))
-	fmt.Printf("privKeyChecksum: %x\n", privKeyChecksum)
-
-	privKeyAddChecksumUncomp := append(privKeyPlusVersion, privKeyChecksum...) // privkey version + privkey hash + extra byte + last
-	fmt.Printf("Privkey version + privkey hash + Uncompressed Checksum: %x   byte length: %d\n", privKeyAddChecksumUncomp, len(privKeyAddChecksumUncomp))
-
-	wifUncompressed := base58(privKeyAddChecksumUncomp)
-	fmt.Println("WIF (Uncompressed):", wifUncompressed, "\n")
+	fmt.Printf("Private Key version byte: %d\n", []byte{net.PrivateKeyID})
+	fmt.Println("WIF of the address above:", privKey.WIF(net, compressed))
+	fmt.Println()
+	fmt.Println("WIF (Uncompressed):", privKey.WIF(net, false), "\n")
+	fmt.Println("WIF (Compressed):", privKey.WIF(net, true), "\n")
 
 	/*
-	 * WIF Key (Compressed) need extra byte added to the end
-	 * along with version + privkey hash + 01
+	 * ParseAddress works out the encoding, network and type of any
+	 * address, and the scriptPubKey paying to it.
 	 */
-	byteone := []byte{0x01}                                  // Extra byte 01 to add to PrivKey at the end
-	privKeyVerByte := append(privKeyPlusVersion, byteone...) // privkey version + privkey hash + extra byte
-	privKeyChecksumComp := s256(s256(privKeyVerByte))[:4]    // first 4 bytes of double hashed (privkey version + privkey hash + extra byte)
-	fmt.Printf("byteone: %d\n", byteone)
-	fmt.Printf("bitcoin version + private key hash + 01 byte: %x   byte length: %d\n", privKeyVerByte, len(privKeyVerByte))
-	fmt.Printf("privKey Checksum for Compressed WIF: %x\n", privKeyChecksumComp)
-
-	privKeyAddChecksum := append(privKeyVerByte, privKeyChecksumComp...) // privkey version + privkey hash + extra byte + last
-	fmt.Printf("Privkey version + privkey hash + extra byte + Compressed Checksum: %x   byte length: %d\n", privKeyAddChecksum, len(privKeyAddChecksum))
-
-	wifCompressed := base58(privKeyAddChecksum)
-	fmt.Println("WIF (Compressed):", wifCompressed, "\n")
+	for _, a := range []string{address, onionAddress} {
+		parsed, err := kmdutil.ParseAddress(a)
+		if err != nil {
+			fmt.Println("parse error:", err)
+			continue
+		}
+		fmt.Printf("%s: %s %s %v payload: %x scriptPubKey: %x\n", a,
+			parsed.Encoding, parsed.Type, parsed.Network, parsed.Payload, parsed.ScriptPubKey)
+	}
 }
@@ -0,0 +1,74 @@
+package main
+
+// SLIP-132 extended key conversion practice code.
+//
+// Please note that the following code is a demo.  Edge cases and error
+// checking are intentionally omitted where they might otherwise distract
+// us from the core ideas.
+//
+//	go run slip132.go                 # BIP84 test vector zpub
+//	go run slip132.go <xpub|ypub|zpub|...>
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"btc-practice/kmdutil"
+)
+
+func main() {
+	key := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	if len(os.Args) > 1 {
+		key = os.Args[1]
+	}
+	k, err := kmdutil.ParseExtendedKey(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("key:", key)
+	fmt.Println("network:", k.Net, " script type:", k.Script, " private:", k.IsPrivate())
+
+	/*
+	 * Only the four version bytes change between the encodings, so the
+	 * chain code and key are the same in all of them.
+	 */
+	fmt.Println("\nencodings:")
+	for _, t := range []kmdutil.ScriptType{kmdutil.ScriptP2PKH, kmdutil.ScriptP2SHP2WPKH,
+		kmdutil.ScriptP2SHP2WSH, kmdutil.ScriptP2WPKH, kmdutil.ScriptP2WSH, kmdutil.ScriptP2TR} {
+		s, err := kmdutil.ConvertExtendedKey(key, t)
+		if errors.Is(err, kmdutil.ErrNoSLIP132Version) {
+			// Taproot wallets use a plain xpub and keep the script type
+			// in a tr(...) descriptor instead.
+			fmt.Printf("  %-12s %v\n", t, err)
+			continue
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("  %-12s %s\n", t, s)
+	}
+
+	/*
+	 * The script type of the key picks the address encoder of its
+	 * children: a zpub gives bc1q..., a ypub 3... and an xpub 1...
+	 */
+	fmt.Println("\nreceive addresses:")
+	for i := 0; i < 3; i++ {
+		path := fmt.Sprintf("m/0/%d", i)
+		c, err := k.Derive(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		addr, err := c.Address()
+		if errors.Is(err, kmdutil.ErrMultisigScriptType) {
+			fmt.Println("  skipped:", err)
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("  %-8s %s\n", path, addr)
+	}
+}